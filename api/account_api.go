@@ -2,35 +2,69 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"form3-interview-accounts/internal/util"
+	"form3-interview-accounts/internal/validation"
 	"form3-interview-accounts/model"
+	"form3-interview-accounts/tracing"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type AccountApi struct {
-	url string
+	url             string
+	httpClient      *http.Client
+	timeout         *time.Duration
+	transport       http.RoundTripper
+	userAgent       string
+	headers         http.Header
+	retryPolicy     RetryPolicy
+	metrics         Metrics
+	tracer          tracing.Tracer
+	authenticator   Authenticator
+	rateLimiter     *RateLimiter
+	logger          Logger
+	logBodies       bool
+	redactionPolicy *RedactionPolicy
 }
 
 const healthyPath = "/v1/health"
 const createAccountPath = "/v1/organisation/accounts"
 const getAllAccountsPath = "/v1/organisation/accounts"
-const getAccountPath = "/v1/organisation/accounts/%s"
-const deleteAccountPath = "/v1/organisation/accounts/%s?version=%d"
 const applicationJsonContentType = "application/json"
 
-func NewAccountApi(url string) (*AccountApi, error) {
+// NewAccountApi creates an AccountApi for the account API at url. Without options
+// requests are sent through a client that behaves like http.DefaultClient.
+func NewAccountApi(url string, options ...Option) (*AccountApi, error) {
 	_, err := validUrl(url)
 	if err != nil {
 		return nil, err
 	}
 
 	form3Api := &AccountApi{
-		url: removeSlashEndOfHostname(url),
+		url:        removeSlashEndOfHostname(url),
+		httpClient: &http.Client{},
+		headers:    http.Header{},
+	}
+
+	for _, option := range options {
+		err = option(form3Api)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account api option. Error: %w", err)
+		}
+	}
+	if form3Api.timeout != nil {
+		form3Api.httpClient.Timeout = *form3Api.timeout
+	}
+	if form3Api.transport != nil {
+		form3Api.httpClient.Transport = form3Api.transport
 	}
 
 	return form3Api, nil
@@ -54,12 +88,26 @@ func removeSlashEndOfHostname(url string) string {
 }
 
 func (form3Api AccountApi) IsHealthy() error {
-	resp, err := http.Get(form3Api.getUrl(healthyPath, nil))
+	return form3Api.IsHealthyWithContext(context.Background())
+}
+
+// IsHealthyWithContext is like IsHealthy but aborts the health check once ctx is done.
+func (form3Api AccountApi) IsHealthyWithContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, form3Api.getUrl(healthyPath, nil), nil)
+	if err != nil {
+		return fmt.Errorf("error creating healthy status request. Error: %w", err)
+	}
+
+	resp, err := form3Api.do(req, OperationHealth, true, nil)
 	if err != nil {
-		return fmt.Errorf("error checking healthy status. Error: %s", err)
+		return fmt.Errorf("error checking healthy status. Error: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error checking healthy status. Error: %w", newAPIError(resp))
+	}
+
 	var data model.HealthyData
 	err = util.FromJsonToModel(resp.Body, &data)
 	if err != nil {
@@ -70,41 +118,76 @@ func (form3Api AccountApi) IsHealthy() error {
 	return nil
 }
 
-func (form3Api AccountApi) GetAccounts(filters map[string]string) ([]model.Account, error) {
-	resp, err := http.Get(form3Api.getUrl(getAllAccountsPath, filters))
+func (form3Api AccountApi) GetAccounts(filter model.AccountFilter) ([]model.Account, error) {
+	return form3Api.GetAccountsWithContext(context.Background(), filter)
+}
+
+// GetAccountsWithContext is like GetAccounts but aborts the request once ctx is done.
+func (form3Api AccountApi) GetAccountsWithContext(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+	data, err := form3Api.GetAccountsPageWithContext(ctx, filter, model.PageRequest{})
 	if err != nil {
-		return []model.Account{}, fmt.Errorf("error fetching list of accounts. Error: %s", err)
+		return []model.Account{}, err
+	}
+
+	return data.Data, nil
+}
+
+// GetAccountsPage fetches a single page of accounts along with the links to the other pages.
+func (form3Api AccountApi) GetAccountsPage(filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error) {
+	return form3Api.GetAccountsPageWithContext(context.Background(), filter, page)
+}
+
+// GetAccountsPageWithContext is like GetAccountsPage but aborts the request once ctx is done.
+func (form3Api AccountApi) GetAccountsPageWithContext(ctx context.Context, filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error) {
+	err := validation.ValidateAccountFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, form3Api.getUrl(getAllAccountsPath, pageQueryParameters(filter.QueryParameters(), page)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating list of accounts request. Error: %w", err)
+	}
+
+	resp, err := form3Api.do(req, OperationList, true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching list of accounts. Error: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		readBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error fetching accounts with status %d response: %s", resp.StatusCode, string(readBytes))
+		return nil, fmt.Errorf("error fetching accounts. Error: %w", newAPIError(resp))
 	}
 
 	var data model.AccountsData
 	err = util.FromJsonToModel(resp.Body, &data)
 	if err != nil {
-		return []model.Account{}, fmt.Errorf("unable to parse json response for list of accounts. Error: %s", err)
+		return nil, fmt.Errorf("unable to parse json response for list of accounts. Error: %s", err)
 	}
 
-	return data.Data, nil
+	return &data, nil
+}
+
+// ListAccounts returns an iterator over every account matching filter, fetched pageSize at a time.
+func (form3Api AccountApi) ListAccounts(ctx context.Context, filter model.AccountFilter, pageSize int) *model.AccountIterator {
+	return model.NewAccountIterator(ctx, pageSize, func(ctx context.Context, page model.PageRequest) (*model.AccountsData, error) {
+		return form3Api.GetAccountsPageWithContext(ctx, filter, page)
+	})
 }
 
 func (form3Api AccountApi) GetAccount(id string) (*model.Account, error) {
-	resp, err := http.Get(form3Api.getUrl(fmt.Sprintf(getAccountPath, id), nil))
-	if err != nil {
-		return nil, fmt.Errorf("error fetching account %s. Error: %s", id, err)
-	}
-	defer resp.Body.Close()
+	return form3Api.GetAccountWithContext(context.Background(), id)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		readBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error fetching account %s with status %d response: %s", id, resp.StatusCode, string(readBytes))
+// GetAccountWithContext is like GetAccount but aborts the request once ctx is done.
+func (form3Api AccountApi) GetAccountWithContext(ctx context.Context, id string) (*model.Account, error) {
+	body, err := form3Api.getAccountBody(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
 	var data model.AccountData
-	err = util.FromJsonToModel(resp.Body, &data)
+	err = json.Unmarshal(body, &data)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse json response for account. Error: %s", err)
 	}
@@ -112,41 +195,104 @@ func (form3Api AccountApi) GetAccount(id string) (*model.Account, error) {
 	return &data.Data, nil
 }
 
+// getAccountBody fetches the account with id and returns the undecoded response body.
+func (form3Api AccountApi) getAccountBody(ctx context.Context, id string) ([]byte, error) {
+	path, err := accountPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, form3Api.getUrl(path, nil), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating fetch account %s request. Error: %w", id, err)
+	}
+
+	resp, err := form3Api.do(req, OperationGet, true, accountAttributes(id, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching account %s. Error: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error fetching account %s. Error: %w", id, newAPIError(resp))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading account %s. Error: %w", id, err)
+	}
+	return body, nil
+}
+
 func (form3Api AccountApi) DeleteAccount(id string, version int) error {
-	req, err := http.NewRequest(http.MethodDelete, form3Api.getUrl(fmt.Sprintf(deleteAccountPath, id, version), nil), nil)
+	return form3Api.DeleteAccountWithContext(context.Background(), id, version)
+}
+
+// DeleteAccountWithContext is like DeleteAccount but aborts the request once ctx is done.
+func (form3Api AccountApi) DeleteAccountWithContext(ctx context.Context, id string, version int) error {
+	path, err := accountPath(id)
 	if err != nil {
-		return fmt.Errorf("error creating delete account %s. Error: %s", id, err)
+		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, form3Api.getUrl(path, map[string]string{"version": strconv.Itoa(version)}), nil)
+	if err != nil {
+		return fmt.Errorf("error creating delete account %s. Error: %w", id, err)
+	}
 
+	resp, attempts, err := form3Api.doCountingAttempts(req, OperationDelete, true, accountAttributes(id, ""))
 	if err != nil {
-		return fmt.Errorf("failed to delete account %s error: %s", id, err)
+		return fmt.Errorf("failed to delete account %s error: %w", id, err)
 	}
+	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound && attempts > 1 {
+		// An earlier attempt may have deleted the account before its response was lost.
+		return nil
+	}
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		readBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete account %s with status code %d response: %s", id, resp.StatusCode, string(readBytes))
+		return fmt.Errorf("failed to delete account %s error: %w", id, newAPIError(resp))
 	}
 
 	return nil
 }
 
 func (form3Api AccountApi) CreateAccount(account model.AccountData) (*model.Account, error) {
+	return form3Api.CreateAccountWithContext(context.Background(), account)
+}
+
+// CreateAccountWithContext is like CreateAccount but aborts the request once ctx is done.
+func (form3Api AccountApi) CreateAccountWithContext(ctx context.Context, account model.AccountData) (*model.Account, error) {
 	marshaledData, err := json.Marshal(account)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing request account body %#v. Error: %s", account, err)
 	}
 
-	resp, err := http.Post(form3Api.getUrl(createAccountPath, nil), applicationJsonContentType, bytes.NewBuffer(marshaledData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, form3Api.getUrl(createAccountPath, nil), bytes.NewBuffer(marshaledData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating create account request. Error: %w", err)
+	}
+	req.Header.Set("Content-Type", applicationJsonContentType)
+
+	resp, attempts, err := form3Api.doCountingAttempts(req, OperationCreate, account.Data.ID != "", accountAttributes(account.Data.ID, account.Data.OrganisationID))
 	if err != nil {
-		return nil, fmt.Errorf("error creating account %#v. Error: %s", account, err)
+		return nil, fmt.Errorf("error creating account %#v. Error: %w", account, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict && attempts > 1 {
+		// An earlier attempt may have created the account before its response was lost.
+		conflictErr := newAPIError(resp)
+		if body, err := form3Api.getAccountBody(ctx, account.Data.ID); err == nil && sameAccount(account.Data, body) {
+			var created model.AccountData
+			if err = json.Unmarshal(body, &created); err == nil {
+				return &created.Data, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create account %s. Error: %w", account.Data.ID, conflictErr)
+	}
 	if resp.StatusCode != http.StatusCreated {
-		readBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("faile to create account with status %d response: %s", resp.StatusCode, string(readBytes))
+		return nil, fmt.Errorf("failed to create account %s. Error: %w", account.Data.ID, newAPIError(resp))
 	}
 
 	var data model.AccountData
@@ -158,6 +304,208 @@ func (form3Api AccountApi) CreateAccount(account model.AccountData) (*model.Acco
 	return &data.Data, nil
 }
 
+// do sends req as operation, retrying it according to the retry policy when it is
+// idempotent, and records the outcome in the metrics and in a span with attributes.
+func (form3Api AccountApi) do(req *http.Request, operation Operation, idempotent bool, attributes map[string]any) (*http.Response, error) {
+	resp, _, err := form3Api.doCountingAttempts(req, operation, idempotent, attributes)
+	return resp, err
+}
+
+// doCountingAttempts is like do but also returns how many attempts were sent.
+func (form3Api AccountApi) doCountingAttempts(req *http.Request, operation Operation, idempotent bool, attributes map[string]any) (*http.Response, int, error) {
+	ctx, span := form3Api.startSpan(req.Context(), operation, req, attributes)
+	defer span.End()
+
+	start := time.Now()
+	resp, attempts, err := form3Api.doWithRetries(req.WithContext(ctx), idempotent)
+	form3Api.observe(operation, resp, err, time.Since(start))
+	endSpan(span, resp, err)
+	return resp, attempts, err
+}
+
+func (form3Api AccountApi) doWithRetries(req *http.Request, idempotent bool) (*http.Response, int, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.Clone(ctx)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempt - 1, err
+			}
+			attemptReq.Body = body
+		}
+
+		resp, err := form3Api.send(attemptReq)
+		if !idempotent || attempt >= form3Api.retryPolicy.MaxAttempts || !shouldRetry(ctx, resp, err) {
+			return resp, attempt, err
+		}
+
+		wait, ok := retryAfter(resp, time.Now())
+		if !ok {
+			wait = form3Api.retryPolicy.backoff(attempt)
+		}
+		if budget := form3Api.retryPolicy.MaxElapsedTime; budget > 0 && time.Since(start)+wait > budget {
+			return resp, attempt, err
+		}
+
+		discardBody(resp)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+func (form3Api AccountApi) send(req *http.Request) (*http.Response, error) {
+	for key, values := range form3Api.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if form3Api.userAgent != "" {
+		req.Header.Set("User-Agent", form3Api.userAgent)
+	}
+
+	tracing.Inject(req.Context(), req.Header)
+
+	resp, err := form3Api.sendAuthenticated(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The credentials may have been revoked or expired early, so renew them and try once more.
+	refreshable, ok := form3Api.authenticator.(RefreshableAuthenticator)
+	if !ok || (req.GetBody == nil && req.Body != nil && req.Body != http.NoBody) {
+		return resp, nil
+	}
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retryReq.Body = body
+	}
+	discardBody(resp)
+	refreshable.Invalidate(req)
+
+	return form3Api.sendAuthenticated(retryReq)
+}
+
+func (form3Api AccountApi) sendAuthenticated(req *http.Request) (*http.Response, error) {
+	if form3Api.rateLimiter != nil {
+		if err := form3Api.rateLimiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("error waiting for the rate limiter. Error: %w", err)
+		}
+	}
+	if form3Api.authenticator != nil {
+		if err := form3Api.authenticator.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("error authenticating request. Error: %w", err)
+		}
+	}
+
+	start := time.Now()
+	resp, err := form3Api.httpClient.Do(req)
+	form3Api.logExchange(req, resp, err, time.Since(start))
+	if form3Api.rateLimiter != nil && err == nil {
+		form3Api.rateLimiter.observe(resp)
+	}
+	return resp, err
+}
+
+// PatchAccount changes the given attributes of the account at its current version and
+// returns the updated account with its new version. A stale version fails with an
+// APIError matching ErrConflict.
+func (form3Api AccountApi) PatchAccount(id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error) {
+	return form3Api.PatchAccountWithContext(context.Background(), id, version, attributes)
+}
+
+// PatchAccountWithContext is like PatchAccount but aborts the request once ctx is done.
+func (form3Api AccountApi) PatchAccountWithContext(ctx context.Context, id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error) {
+	path, err := accountPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	currentVersion := int64(version)
+	patch := model.AccountPatchData{
+		Data: model.AccountPatch{
+			Attributes: &attributes,
+			ID:         id,
+			Type:       model.AccountResourceType,
+			Version:    &currentVersion,
+		},
+	}
+	marshaledData, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing request account patch body %#v. Error: %s", patch, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, form3Api.getUrl(path, nil), bytes.NewBuffer(marshaledData))
+	if err != nil {
+		return nil, fmt.Errorf("error creating patch account %s request. Error: %w", id, err)
+	}
+	req.Header.Set("Content-Type", applicationJsonContentType)
+
+	resp, err := form3Api.do(req, OperationPatch, false, accountAttributes(id, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error patching account %s. Error: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to patch account %s. Error: %w", id, newAPIError(resp))
+	}
+
+	var data model.AccountData
+	err = util.FromJsonToModel(resp.Body, &data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse json response for patching account. Error: %s", err)
+	}
+
+	return &data.Data, nil
+}
+
+// sameAccount reports whether the stored account body is the account requested, i.e. it
+// has the same organisation, type and every requested attribute value. The stored
+// attributes are compared as decoded JSON, so values this client does not know still match.
+func sameAccount(requested model.Account, stored []byte) bool {
+	var storedData struct {
+		Data struct {
+			ID             string         `json:"id"`
+			OrganisationID string         `json:"organisation_id"`
+			Type           string         `json:"type"`
+			Attributes     map[string]any `json:"attributes"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(stored, &storedData) != nil {
+		return false
+	}
+	if requested.ID != storedData.Data.ID || requested.OrganisationID != storedData.Data.OrganisationID || requested.Type != storedData.Data.Type {
+		return false
+	}
+
+	var requestedAttributes map[string]any
+	marshaledRequested, err := json.Marshal(requested.Attributes)
+	if err != nil || json.Unmarshal(marshaledRequested, &requestedAttributes) != nil {
+		return false
+	}
+	for key, value := range requestedAttributes {
+		if !reflect.DeepEqual(value, storedData.Data.Attributes[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+func accountPath(id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("account id must not be empty")
+	}
+	return getAllAccountsPath + "/" + util.EscapePathSegment(id), nil
+}
+
 func (form3Api AccountApi) getUrl(path string, filters map[string]string) string {
 	return util.BuildUrl(form3Api.url, path, filters)
 }
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"form3-interview-accounts/model"
+	"io"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jarcoal/httpmock"
@@ -52,7 +57,7 @@ func TestGetAccounts(t *testing.T) {
 	defer httpmock.DeactivateAndReset()
 	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
 		httpmock.NewStringResponder(200, `{     "data": [         {             "attributes": {                 "alternative_names": null,                 "country": "GB",                 "name": []             },             "created_on": "2022-10-19T09:03:08.334Z",             "id": "0d209d7f-d07a-4542-947f-5885fddddae7",             "modified_on": "2022-10-19T09:03:08.334Z",             "organisation_id": "ba61483c-d5c5-4f50-ae81-6b8c039bea43",             "type": "accounts",             "version": 0         },         {             "attributes": {                 "alternative_names": null,                 "country": "GB",                 "name": []             },             "created_on": "2022-10-19T09:05:40.235Z",             "id": "0d209d7f-d07a-4542-947f-5885fddddae8",             "modified_on": "2022-10-19T09:05:40.235Z",             "organisation_id": "ba61483c-d5c5-4f50-ae81-6b8c039bea43",             "type": "accounts",             "version": 0         }] }]`))
-	accounts, err := accountApi.GetAccounts(nil)
+	accounts, err := accountApi.GetAccounts(model.AccountFilter{})
 	assert.Empty(t, err, "Error is not empty")
 	assert.NotEmpty(t, accounts, "Accounts list is empty")
 	assert.Equal(t, len(accounts), 2)
@@ -64,12 +69,48 @@ func TestFailedGetAccounts(t *testing.T) {
 	defer httpmock.DeactivateAndReset()
 	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
 		httpmock.NewStringResponder(400, ``))
-	accounts, err := accountApi.GetAccounts(nil)
+	accounts, err := accountApi.GetAccounts(model.AccountFilter{})
 	assert.NotEmpty(t, err, "Error is not empty")
 	assert.Empty(t, accounts, "Accounts list is empty")
 	assert.Equal(t, len(accounts), 0)
 }
 
+func TestGetAccountsWithFilter(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		"filter[bank_id]=400300&filter[bank_id_code]=GBDSC&filter[country]=GB&filter[status]=confirmed",
+		httpmock.NewStringResponder(200, `{"data": [{"id": "0d209d7f-d07a-4542-947f-5885fddddae7"}]}`))
+	accounts, err := accountApi.GetAccounts(model.AccountFilter{
+		BankID:     "400300",
+		BankIDCode: "GBDSC",
+		Country:    "GB",
+		Status:     "confirmed",
+	})
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, len(accounts), 1)
+}
+
+func TestGetAccountsWithInvalidFilter(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	invalidFilters := []model.AccountFilter{
+		{Country: "XX"},
+		{Status: "confirmed "},
+		{Status: "closed"},
+		{BankID: " 400300"},
+	}
+
+	for _, filter := range invalidFilters {
+		accounts, err := accountApi.GetAccounts(filter)
+		assert.NotEmpty(t, err, "Error is empty for filter %#v", filter)
+		assert.Empty(t, accounts, "Accounts list is not empty")
+	}
+	assert.Equal(t, 0, httpmock.GetTotalCallCount(), "Invalid filter was sent")
+}
+
 func TestGetAccount(t *testing.T) {
 	accountApi, _ := getAccountApi()
 	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
@@ -83,6 +124,19 @@ func TestGetAccount(t *testing.T) {
 	assert.Equal(t, account.ID, "0d209d7f-d07a-4542-947f-5885fddddae7")
 }
 
+func TestGetAccountsWithUnknownEnumValues(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		httpmock.NewStringResponder(200, `{"data": [{"attributes": {"country": "DK", "bank_id_code": "DKNCC", "status": "closed"}, "id": "0d209d7f-d07a-4542-947f-5885fddddae7", "type": "accounts"}, {"attributes": {"country": "GB", "bank_id_code": "GBDSC"}, "id": "1d209d7f-d07a-4542-947f-5885fddddae7", "type": "accounts"}]}`))
+	accounts, err := accountApi.GetAccounts(model.AccountFilter{})
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 2, len(accounts))
+	assert.Equal(t, model.BankIDCode("DKNCC"), accounts[0].Attributes.BankIDCode)
+	assert.Equal(t, model.AccountStatus("closed"), *accounts[0].Attributes.Status)
+}
+
 func TestGetNotFoundAccount(t *testing.T) {
 	accountApi, _ := getAccountApi()
 	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
@@ -95,6 +149,29 @@ func TestGetNotFoundAccount(t *testing.T) {
 	assert.Empty(t, account, "Account is empty")
 }
 
+func TestAccountIdCannotAlterRequestPath(t *testing.T) {
+	var requests []*http.Request
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return stringResponse(404, ``), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport))
+
+	_, err := accountApi.GetAccount("../health")
+	assert.True(t, IsNotFound(err))
+	err = accountApi.DeleteAccount("..", 0)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, "/v1/organisation/accounts/..%2Fhealth", requests[0].URL.EscapedPath())
+	assert.Equal(t, "/v1/organisation/accounts/%2E%2E", requests[1].URL.EscapedPath())
+	assert.Equal(t, "version=0", requests[1].URL.RawQuery)
+
+	_, err = accountApi.GetAccount("")
+	assert.NotEmpty(t, err, "Error is empty")
+	err = accountApi.DeleteAccount("", 0)
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Equal(t, 2, len(requests), "Request without account id was sent")
+}
+
 func TestCreateAccount(t *testing.T) {
 	accountApi, _ := getAccountApi()
 	var version int64 = 0
@@ -123,6 +200,41 @@ func TestCreateAccount(t *testing.T) {
 	assert.Equal(t, account.ID, uuidString)
 }
 
+func TestPatchAccount(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	status := model.AccountStatusConfirmed
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var sentBody string
+	httpmock.RegisterResponder("PATCH", fmt.Sprintf("%s/v1/organisation/accounts/%s", hostname, id),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			sentBody = string(body)
+			return httpmock.NewStringResponse(200, `{"data": {"attributes": {"country": "GB", "name": ["Sam Holder"], "status": "confirmed"}, "id": "`+id+`", "type": "accounts", "version": 1}}`), nil
+		})
+	account, err := accountApi.PatchAccount(id, 0, model.AccountAttributesPatch{
+		Name:   []string{"Sam Holder"},
+		Status: &status,
+	})
+	assert.Empty(t, err, "Error is not empty")
+	assert.JSONEq(t, `{"data": {"attributes": {"name": ["Sam Holder"], "status": "confirmed"}, "id": "`+id+`", "type": "accounts", "version": 0}}`, sentBody)
+	assert.Equal(t, int64(1), *account.Version)
+	assert.Equal(t, []string{"Sam Holder"}, account.Attributes.Name)
+}
+
+func TestPatchAccountWithStaleVersion(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("PATCH", fmt.Sprintf("%s/v1/organisation/accounts/%s", hostname, id),
+		httpmock.NewStringResponder(409, `{"error_message": "invalid version"}`))
+	account, err := accountApi.PatchAccount(id, 0, model.AccountAttributesPatch{Name: []string{"Sam Holder"}})
+	assert.True(t, IsConflict(err))
+	assert.Empty(t, account, "Account is not empty")
+}
+
 func TestSuccessfulDeletingAccount(t *testing.T) {
 	accountApi, _ := getAccountApi()
 	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
@@ -147,6 +259,86 @@ func TestErrorDeletingAccount(t *testing.T) {
 	assert.NotEmpty(t, err, "Error is empty")
 }
 
+func TestCancelledContextAbortsInFlightRequests(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	slowResponder := httpmock.NewStringResponder(200, `{}`).Delay(5 * time.Second)
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/health", hostname), slowResponder)
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname), slowResponder)
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts/%s", hostname, id), slowResponder)
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/v1/organisation/accounts/%s?version=0", hostname, id), slowResponder)
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/v1/organisation/accounts", hostname), slowResponder)
+
+	calls := map[string]func(ctx context.Context) error{
+		"health": func(ctx context.Context) error {
+			return accountApi.IsHealthyWithContext(ctx)
+		},
+		"list": func(ctx context.Context) error {
+			_, err := accountApi.GetAccountsWithContext(ctx, model.AccountFilter{})
+			return err
+		},
+		"get": func(ctx context.Context) error {
+			_, err := accountApi.GetAccountWithContext(ctx, id)
+			return err
+		},
+		"delete": func(ctx context.Context) error {
+			return accountApi.DeleteAccountWithContext(ctx, id, 0)
+		},
+		"create": func(ctx context.Context) error {
+			_, err := accountApi.CreateAccountWithContext(ctx, model.AccountData{})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				cancel()
+			}()
+
+			start := time.Now()
+			err := call(ctx)
+			assert.True(t, errors.Is(err, context.Canceled), "Error is not context.Canceled: %v", err)
+			assert.Less(t, time.Since(start), time.Second, "Request was not aborted")
+		})
+	}
+}
+
+func TestContextDeadlineAbortsRequest(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		httpmock.NewStringResponder(200, `{"data": []}`).Delay(5*time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	accounts, err := accountApi.GetAccountsWithContext(ctx, model.AccountFilter{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error is not context.DeadlineExceeded: %v", err)
+	assert.Empty(t, accounts, "Accounts list is not empty")
+}
+
 func getAccountApi() (*AccountApi, error) {
 	return NewAccountApi(hostname)
 }
+
+func getAccountData() model.AccountData {
+	var version int64 = 0
+	country := "GB"
+	uuidString := uuid.New().String()
+	return model.AccountData{
+		Data: model.Account{
+			ID:             uuidString,
+			OrganisationID: uuidString,
+			Type:           "accounts",
+			Version:        &version,
+			Attributes: &model.AccountAttributes{
+				Name:    []string{"Samantha Holder"},
+				Country: &country,
+			},
+		},
+	}
+}
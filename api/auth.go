@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTargetHeader = "(request-target)"
+
+// Authenticator adds credentials to a request. It is called for every attempt, right
+// before the request is sent, so time based credentials are always fresh.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// RefreshableAuthenticator is an Authenticator whose credentials can be renewed. A request
+// rejected with 401 Unauthorized is sent once more after calling Invalidate.
+type RefreshableAuthenticator interface {
+	Authenticator
+	// Invalidate discards the credentials used to authenticate req, unless they were
+	// already replaced.
+	Invalidate(req *http.Request)
+}
+
+// WithAuthenticator authenticates every request with authenticator.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(form3Api *AccountApi) error {
+		if authenticator == nil {
+			return fmt.Errorf("authenticator must not be nil")
+		}
+		form3Api.authenticator = authenticator
+		return nil
+	}
+}
+
+// BearerTokenAuthenticator sends a fixed token in the Authorization header.
+type BearerTokenAuthenticator struct {
+	Token string
+}
+
+func (authenticator BearerTokenAuthenticator) Authenticate(req *http.Request) error {
+	if authenticator.Token == "" {
+		return fmt.Errorf("bearer token must not be empty")
+	}
+	req.Header.Set("Authorization", "Bearer "+authenticator.Token)
+	return nil
+}
+
+// HTTPSignatureAuthenticator signs requests with an RSA key following the draft-cavage
+// HTTP signatures specification, as the Form3 API expects. It signs the request target,
+// host and date, plus the digest, content type and content length of requests with a body.
+type HTTPSignatureAuthenticator struct {
+	keyID      string
+	privateKey *rsa.PrivateKey
+	now        func() time.Time
+}
+
+// NewHTTPSignatureAuthenticator creates an authenticator signing with privateKey, which
+// the API knows under keyID.
+func NewHTTPSignatureAuthenticator(keyID string, privateKey *rsa.PrivateKey) (*HTTPSignatureAuthenticator, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("key id must not be empty")
+	}
+	if privateKey == nil {
+		return nil, fmt.Errorf("private key must not be nil")
+	}
+	return &HTTPSignatureAuthenticator{keyID: keyID, privateKey: privateKey, now: time.Now}, nil
+}
+
+// ParseRSAPrivateKey parses a PEM encoded RSA private key in PKCS #1 or PKCS #8 form.
+func ParseRSAPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key. Error: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is a %T, not an RSA key", key)
+	}
+	return rsaKey, nil
+}
+
+func (authenticator *HTTPSignatureAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set("Date", authenticator.now().UTC().Format(http.TimeFormat))
+	headers := []string{requestTargetHeader, "host", "date"}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := readBody(req)
+		if err != nil {
+			return fmt.Errorf("unable to read body to sign. Error: %w", err)
+		}
+		req.Header.Set("Digest", Digest(body))
+		req.Header.Set("Content-Length", strconv.FormatInt(req.ContentLength, 10))
+		headers = append(headers, "digest", "content-type", "content-length")
+	}
+
+	signingString, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(signingString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, authenticator.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return fmt.Errorf("unable to sign request. Error: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		authenticator.keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+// Digest returns the Digest header value of body: its base64 encoded SHA-256 hash.
+func Digest(body []byte) string {
+	hash := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// readBody returns the body of req, read through GetBody when possible so req.Body is
+// left untouched. Otherwise req.Body is consumed and replaced.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+
+	content, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(content))
+	req.ContentLength = int64(len(content))
+	return content, nil
+}
+
+// signingString builds the string to sign out of headers, one "name: value" line each.
+func signingString(req *http.Request, headers []string) (string, error) {
+	lines := make([]string, 0, len(headers))
+	for _, header := range headers {
+		var value string
+		switch header {
+		case requestTargetHeader:
+			value = strings.ToLower(req.Method) + " " + req.URL.RequestURI()
+		case "host":
+			value = req.Host
+			if value == "" {
+				value = req.URL.Host
+			}
+		default:
+			value = req.Header.Get(header)
+		}
+		if value == "" {
+			return "", fmt.Errorf("header %s to sign is missing", header)
+		}
+		lines = append(lines, header+": "+value)
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,159 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var signatureFormat = regexp.MustCompile(`^Signature keyId="([^"]+)",algorithm="rsa-sha256",headers="([^"]+)",signature="([^"]+)"$`)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Empty(t, err, "Error is not empty")
+	return key
+}
+
+// verifySignature checks the Authorization header of req against the public key and the
+// expected signing string, and returns the signed headers.
+func verifySignature(t *testing.T, req *http.Request, publicKey *rsa.PublicKey, expected string) []string {
+	match := signatureFormat.FindStringSubmatch(req.Header.Get("Authorization"))
+	assert.NotEmpty(t, match, "Authorization header is not a signature")
+	assert.Equal(t, "key-1", match[1])
+
+	signature, _ := base64.StdEncoding.DecodeString(match[3])
+	hashed := sha256.Sum256([]byte(expected))
+	assert.Empty(t, rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature), "Signature is invalid")
+	return strings.Split(match[2], " ")
+}
+
+func TestDigest(t *testing.T) {
+	assert.Equal(t, "SHA-256=47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", Digest([]byte{}))
+	assert.Equal(t, "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", Digest([]byte(`{"hello": "world"}`)))
+}
+
+func TestSignedRequests(t *testing.T) {
+	key := generateKey(t)
+	authenticator, err := NewHTTPSignatureAuthenticator("key-1", key)
+	assert.Empty(t, err, "Error is not empty")
+	authenticator.now = func() time.Time { return time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC) }
+
+	var requests []*http.Request
+	var bodies []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := ""
+		if req.Body != nil {
+			content, _ := io.ReadAll(req.Body)
+			body = string(content)
+		}
+		requests = append(requests, req)
+		bodies = append(bodies, body)
+		if req.Method == http.MethodPost {
+			return stringResponse(201, `{"data": {}}`), nil
+		}
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithAuthenticator(authenticator))
+	assert.Empty(t, err, "Error is not empty")
+
+	err = accountApi.IsHealthy()
+	assert.Empty(t, err, "Error is not empty")
+	_, err = accountApi.CreateAccount(getAccountData())
+	assert.Empty(t, err, "Error is not empty")
+
+	assert.Equal(t, "Thu, 04 Mar 2021 10:00:00 GMT", requests[0].Header.Get("Date"))
+	expected := "(request-target): get /v1/health\n" +
+		"host: localhost:8080\n" +
+		"date: Thu, 04 Mar 2021 10:00:00 GMT"
+	assert.Equal(t, []string{"(request-target)", "host", "date"}, verifySignature(t, requests[0], &key.PublicKey, expected))
+	assert.Empty(t, requests[0].Header.Get("Digest"))
+
+	expected = "(request-target): post /v1/organisation/accounts\n" +
+		"host: localhost:8080\n" +
+		"date: Thu, 04 Mar 2021 10:00:00 GMT\n" +
+		"digest: " + Digest([]byte(bodies[1])) + "\n" +
+		"content-type: application/json\n" +
+		"content-length: " + strconv.Itoa(len(bodies[1]))
+	assert.Equal(t, []string{"(request-target)", "host", "date", "digest", "content-type", "content-length"}, verifySignature(t, requests[1], &key.PublicKey, expected))
+	assert.Equal(t, Digest([]byte(bodies[1])), requests[1].Header.Get("Digest"))
+	assert.NotEmpty(t, bodies[1], "Body was consumed by the signature")
+}
+
+func TestSignatureDetectsTampering(t *testing.T) {
+	key := generateKey(t)
+	authenticator, _ := NewHTTPSignatureAuthenticator("key-1", key)
+	authenticator.now = func() time.Time { return time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC) }
+	req, _ := http.NewRequest(http.MethodDelete, hostname+"/v1/organisation/accounts/1?version=0", nil)
+
+	err := authenticator.Authenticate(req)
+	assert.Empty(t, err, "Error is not empty")
+	verifySignature(t, req, &key.PublicKey, "(request-target): delete /v1/organisation/accounts/1?version=0\n"+
+		"host: localhost:8080\n"+
+		"date: Thu, 04 Mar 2021 10:00:00 GMT")
+
+	match := signatureFormat.FindStringSubmatch(req.Header.Get("Authorization"))
+	req.URL.RawQuery = "version=1"
+	tampered, _ := signingString(req, strings.Split(match[2], " "))
+	signature, _ := base64.StdEncoding.DecodeString(match[3])
+	hashed := sha256.Sum256([]byte(tampered))
+	assert.NotEmpty(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], signature), "Signature is valid for another request")
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key := generateKey(t)
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Bytes, _ := x509.MarshalPKCS8PrivateKey(key)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	for _, encoded := range [][]byte{pkcs1, pkcs8} {
+		parsed, err := ParseRSAPrivateKey(encoded)
+		assert.Empty(t, err, "Error is not empty")
+		assert.True(t, key.Equal(parsed), "Parsed key differs")
+	}
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecBytes, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	_, err := ParseRSAPrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecBytes}))
+	assert.NotEmpty(t, err, "Error is empty for an EC key")
+
+	_, err = ParseRSAPrivateKey([]byte("not a key"))
+	assert.NotEmpty(t, err, "Error is empty")
+}
+
+func TestNilAuthenticator(t *testing.T) {
+	accountApi, err := NewAccountApi(hostname, WithAuthenticator(nil))
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Empty(t, accountApi, "Account API is not empty")
+}
+
+func TestBearerTokenAuthenticator(t *testing.T) {
+	var authorization string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		authorization = req.Header.Get("Authorization")
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithAuthenticator(BearerTokenAuthenticator{Token: "secret"}))
+
+	err := accountApi.IsHealthy()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "Bearer secret", authorization)
+
+	accountApi, _ = NewAccountApi(hostname, WithTransport(transport), WithAuthenticator(BearerTokenAuthenticator{}))
+	err = accountApi.IsHealthy()
+	assert.NotEmpty(t, err, "Error is empty")
+}
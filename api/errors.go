@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3-interview-accounts/model"
+	"io"
+	"net/http"
+)
+
+const requestIdHeader = "X-Request-Id"
+
+var (
+	ErrNotFound   = errors.New("account api resource not found")
+	ErrConflict   = errors.New("account api resource conflict")
+	ErrValidation = errors.New("account api request is invalid")
+)
+
+// APIError is returned when the account API answers with an unexpected status code.
+// It matches ErrNotFound, ErrConflict and ErrValidation with errors.Is depending on
+// the status code.
+type APIError struct {
+	StatusCode   int
+	ErrorCode    string
+	ErrorMessage string
+	RequestID    string
+	Body         []byte
+}
+
+type errorBody struct {
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+func (apiError *APIError) Error() string {
+	message := fmt.Sprintf("account api responded with status %d", apiError.StatusCode)
+	if apiError.ErrorCode != "" {
+		message += fmt.Sprintf(" code %s", apiError.ErrorCode)
+	}
+	if apiError.ErrorMessage != "" {
+		message += fmt.Sprintf(": %s", apiError.ErrorMessage)
+	} else if len(apiError.Body) > 0 {
+		message += fmt.Sprintf(" response: %s", string(apiError.Body))
+	}
+	if apiError.RequestID != "" {
+		message += fmt.Sprintf(" (request id %s)", apiError.RequestID)
+	}
+	return message
+}
+
+func (apiError *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return apiError.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return apiError.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return apiError.StatusCode == http.StatusBadRequest || apiError.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// IsNotFound reports whether err is, or wraps, an APIError for a missing resource.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is, or wraps, an APIError for a duplicate resource or a stale version.
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidationError reports whether err is, or wraps, an APIError for a request rejected as
+// invalid or the model.ValidationErrors of a request rejected before it was sent.
+func IsValidationError(err error) bool {
+	var validationErrors model.ValidationErrors
+	return errors.Is(err, ErrValidation) || errors.As(err, &validationErrors)
+}
+
+func newAPIError(resp *http.Response) *APIError {
+	readBytes, _ := io.ReadAll(resp.Body)
+	apiError := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(requestIdHeader),
+		Body:       readBytes,
+	}
+
+	var body errorBody
+	if json.Unmarshal(readBytes, &body) == nil {
+		apiError.ErrorCode = body.ErrorCode
+		apiError.ErrorMessage = body.ErrorMessage
+	}
+
+	return apiError
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"form3-interview-accounts/model"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFoundAPIError(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts/%s", hostname, id),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(404, `{"error_message": "record `+id+` does not exist"}`)
+			resp.Header.Set("X-Request-Id", "4a5b6c")
+			return resp, nil
+		})
+
+	_, err := accountApi.GetAccount(id)
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError), "Error is not an APIError")
+	assert.Equal(t, 404, apiError.StatusCode)
+	assert.Equal(t, "record "+id+" does not exist", apiError.ErrorMessage)
+	assert.Equal(t, "4a5b6c", apiError.RequestID)
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsConflict(err))
+	assert.False(t, IsValidationError(err))
+}
+
+func TestConflictAPIError(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/v1/organisation/accounts/%s?version=3", hostname, id),
+		httpmock.NewStringResponder(409, `{"error_message": "invalid version"}`))
+
+	err := accountApi.DeleteAccount(id, 3)
+	assert.True(t, IsConflict(err))
+	assert.True(t, errors.Is(err, ErrConflict))
+	assert.False(t, IsNotFound(err))
+}
+
+func TestValidationAPIError(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		httpmock.NewStringResponder(400, `{"error_code": "validation_failure", "error_message": "validation failure list:\ncountry in body is required"}`))
+
+	_, err := accountApi.CreateAccount(getAccountData())
+	assert.True(t, IsValidationError(err))
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError), "Error is not an APIError")
+	assert.Equal(t, "validation_failure", apiError.ErrorCode)
+	assert.Contains(t, err.Error(), "country in body is required")
+}
+
+func TestAPIErrorWithoutJsonBody(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		httpmock.NewStringResponder(502, `Bad Gateway`))
+
+	_, err := accountApi.GetAccounts(model.AccountFilter{})
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError), "Error is not an APIError")
+	assert.Equal(t, 502, apiError.StatusCode)
+	assert.Empty(t, apiError.ErrorMessage)
+	assert.Equal(t, "Bad Gateway", string(apiError.Body))
+	assert.Contains(t, err.Error(), "Bad Gateway")
+}
+
+func TestModelValidationErrorsAreValidationErrors(t *testing.T) {
+	err := fmt.Errorf("error creating account. Error: %w", model.ValidationErrors{
+		{Pointer: "/data/attributes/country", Code: model.ValidationCodeRequired, Message: "invalid country, country is missing"},
+	})
+	assert.True(t, IsValidationError(err))
+	assert.False(t, IsNotFound(err))
+	assert.False(t, IsValidationError(errors.New("invalid country")))
+}
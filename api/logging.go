@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// Logger receives a structured record of every request sent by AccountApi. It is
+// satisfied by *slog.Logger; args are alternating keys and values.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// RedactionPolicy lists the account attributes whose values are masked in logged bodies
+// and query filters.
+type RedactionPolicy struct {
+	Fields []string
+}
+
+// DefaultRedactionPolicy masks account numbers, IBANs and names.
+func DefaultRedactionPolicy() RedactionPolicy {
+	return RedactionPolicy{Fields: []string{"account_number", "iban", "name", "alternative_names"}}
+}
+
+// WithLogger makes the AccountApi log method, path, status, latency and request ID of
+// every request at info level, or error level for failures and 5xx responses.
+func WithLogger(logger Logger) Option {
+	return func(form3Api *AccountApi) error {
+		if logger == nil {
+			return fmt.Errorf("logger must not be nil")
+		}
+		form3Api.logger = logger
+		if form3Api.redactionPolicy == nil {
+			policy := DefaultRedactionPolicy()
+			form3Api.redactionPolicy = &policy
+		}
+		return nil
+	}
+}
+
+// WithBodyLogging also logs the redacted request and response bodies at debug level.
+// Bodies are buffered and parsed for redaction, so only enable it while debugging.
+func WithBodyLogging() Option {
+	return func(form3Api *AccountApi) error {
+		form3Api.logBodies = true
+		return nil
+	}
+}
+
+// WithRedactionPolicy replaces DefaultRedactionPolicy. An empty policy logs every value as is.
+func WithRedactionPolicy(policy RedactionPolicy) Option {
+	return func(form3Api *AccountApi) error {
+		form3Api.redactionPolicy = &policy
+		return nil
+	}
+}
+
+func (form3Api AccountApi) logExchange(req *http.Request, resp *http.Response, err error, latency time.Duration) {
+	if form3Api.logger == nil {
+		return
+	}
+	policy := form3Api.redactionPolicy
+
+	args := []any{
+		"method", req.Method,
+		"path", policy.redactUrl(req.URL),
+		"latency", latency,
+	}
+	if err != nil {
+		form3Api.logger.Error("account api request failed", append(args, "error", policy.redactError(err))...)
+		return
+	}
+
+	args = append(args, "status", resp.StatusCode, "request_id", resp.Header.Get(requestIdHeader))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		form3Api.logger.Error("account api request", args...)
+	} else {
+		form3Api.logger.Info("account api request", args...)
+	}
+
+	if !form3Api.logBodies {
+		return
+	}
+	if body := requestBody(req); len(body) > 0 {
+		form3Api.logger.Debug("account api request body", "method", req.Method, "path", policy.redactUrl(req.URL), "body", policy.redactBody(body))
+	}
+	if body := responseBody(resp); len(body) > 0 {
+		form3Api.logger.Debug("account api response body", "method", req.Method, "path", policy.redactUrl(req.URL), "body", policy.redactBody(body))
+	}
+}
+
+// requestBody reads a copy of the body through GetBody, leaving the body to be sent untouched.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	content, _ := io.ReadAll(body)
+	return content
+}
+
+// responseBody buffers the body and puts it back so the caller can still decode it.
+func responseBody(resp *http.Response) []byte {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	var rest io.Reader = bytes.NewReader(content)
+	if err != nil {
+		rest = io.MultiReader(rest, errorReader{err: err})
+	}
+	resp.Body = io.NopCloser(rest)
+	return content
+}
+
+type errorReader struct {
+	err error
+}
+
+func (reader errorReader) Read([]byte) (int, error) {
+	return 0, reader.err
+}
+
+func (policy *RedactionPolicy) redacts(field string) bool {
+	if policy == nil {
+		return false
+	}
+	for _, redactedField := range policy.Fields {
+		if redactedField == field {
+			return true
+		}
+	}
+	return false
+}
+
+// redactUrl returns the path and query of u with the redacted filters masked.
+func (policy *RedactionPolicy) redactUrl(u *url.URL) string {
+	query := u.Query()
+	if len(query) == 0 {
+		return u.EscapedPath()
+	}
+	for key := range query {
+		field := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+		if policy.redacts(field) {
+			query.Set(key, redacted)
+		}
+	}
+	return u.EscapedPath() + "?" + query.Encode()
+}
+
+// redactError masks the query of the URL that *url.Error messages contain.
+func (policy *RedactionPolicy) redactError(err error) string {
+	var urlError *url.Error
+	if !errors.As(err, &urlError) {
+		return err.Error()
+	}
+	requestUrl, parseErr := url.Parse(urlError.URL)
+	if parseErr != nil {
+		return err.Error()
+	}
+	return strings.ReplaceAll(err.Error(), urlError.URL, policy.redactUrl(requestUrl))
+}
+
+// redactBody masks the redacted fields anywhere in a JSON body. Bodies that are not JSON
+// are not logged, since they cannot be redacted.
+func (policy *RedactionPolicy) redactBody(body []byte) string {
+	var content any
+	if err := json.Unmarshal(body, &content); err != nil {
+		return fmt.Sprintf("[%d bytes, not JSON]", len(body))
+	}
+	redactedBody, err := json.Marshal(policy.redactValue(content))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(redactedBody)
+}
+
+func (policy *RedactionPolicy) redactValue(value any) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, fieldValue := range typedValue {
+			if policy.redacts(key) {
+				typedValue[key] = redacted
+			} else {
+				typedValue[key] = policy.redactValue(fieldValue)
+			}
+		}
+	case []any:
+		for i, element := range typedValue {
+			typedValue[i] = policy.redactValue(element)
+		}
+	}
+	return value
+}
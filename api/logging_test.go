@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"form3-interview-accounts/model"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	level   string
+	message string
+	args    map[string]any
+}
+
+type recordingLogger struct {
+	entries []logEntry
+}
+
+func (logger *recordingLogger) record(level string, msg string, args []any) {
+	entry := logEntry{level: level, message: msg, args: map[string]any{}}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry.args[fmt.Sprint(args[i])] = args[i+1]
+	}
+	logger.entries = append(logger.entries, entry)
+}
+
+func (logger *recordingLogger) Debug(msg string, args ...any) { logger.record("debug", msg, args) }
+func (logger *recordingLogger) Info(msg string, args ...any)  { logger.record("info", msg, args) }
+func (logger *recordingLogger) Error(msg string, args ...any) { logger.record("error", msg, args) }
+
+func (logger *recordingLogger) String() string {
+	return fmt.Sprint(logger.entries)
+}
+
+func TestNilLogger(t *testing.T) {
+	accountApi, err := NewAccountApi(hostname, WithLogger(nil))
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Empty(t, accountApi, "Account API is not empty")
+}
+
+func TestLoggerRecordsRequest(t *testing.T) {
+	logger := &recordingLogger{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := stringResponse(200, `{"status": "up"}`)
+		resp.Header.Set("X-Request-Id", "4a5b6c")
+		return resp, nil
+	})
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithLogger(logger))
+	assert.Empty(t, err, "Error is not empty")
+
+	err = accountApi.IsHealthy()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "info", logger.entries[0].level)
+	assert.Equal(t, "GET", logger.entries[0].args["method"])
+	assert.Equal(t, "/v1/health", logger.entries[0].args["path"])
+	assert.Equal(t, 200, logger.entries[0].args["status"])
+	assert.Equal(t, "4a5b6c", logger.entries[0].args["request_id"])
+	assert.Contains(t, logger.entries[0].args, "latency")
+}
+
+func TestLoggerRedactsFiltersAndBodies(t *testing.T) {
+	logger := &recordingLogger{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return stringResponse(201, `{"data": {"attributes": {"country": "GB", "iban": "GB16NWBK40030041426819", "account_number": "41426819", "name": ["Samantha Holder"]}}}`), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithLogger(logger), WithBodyLogging())
+
+	_, _ = accountApi.GetAccounts(model.AccountFilter{Iban: "GB16NWBK40030041426819", Country: "GB"})
+	_, err := accountApi.CreateAccount(getAccountData())
+	assert.Empty(t, err, "Error is not empty")
+
+	logged := logger.String()
+	assert.NotContains(t, logged, "41426819")
+	assert.NotContains(t, logged, "Samantha")
+	assert.Contains(t, logged, "filter%5Biban%5D=%5BREDACTED%5D")
+	assert.Contains(t, logged, `"country":"GB"`)
+
+	var debugEntries int
+	for _, entry := range logger.entries {
+		if entry.level == "debug" {
+			debugEntries++
+		}
+	}
+	assert.Equal(t, 3, debugEntries)
+}
+
+func TestLoggerSkipsBodiesByDefault(t *testing.T) {
+	logger := &recordingLogger{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return stringResponse(201, `{"data": {"attributes": {"name": ["Samantha Holder"]}}}`), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithLogger(logger))
+
+	_, err := accountApi.CreateAccount(getAccountData())
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Equal(t, "info", logger.entries[0].level)
+}
+
+func TestLoggerWithoutRedaction(t *testing.T) {
+	logger := &recordingLogger{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return stringResponse(200, `{"data": []}`), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithLogger(logger), WithRedactionPolicy(RedactionPolicy{}))
+
+	_, _ = accountApi.GetAccounts(model.AccountFilter{AccountNumber: "41426819"})
+	assert.True(t, strings.Contains(logger.String(), "41426819"), "Account number is redacted")
+}
+
+func TestLoggerRecordsFailures(t *testing.T) {
+	logger := &recordingLogger{}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithLogger(logger))
+
+	_, err := accountApi.GetAccounts(model.AccountFilter{AccountNumber: "41426819"})
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Equal(t, "error", logger.entries[0].level)
+	assert.Contains(t, logger.entries[0].args["error"], "connection refused")
+	assert.NotContains(t, logger.String(), "41426819")
+}
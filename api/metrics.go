@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operation names an AccountApi call in metrics.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationGet    Operation = "get"
+	OperationList   Operation = "list"
+	OperationPatch  Operation = "patch"
+	OperationDelete Operation = "delete"
+	OperationHealth Operation = "health"
+)
+
+// Metrics records the outcome of every AccountApi call, including its retries.
+// statusClass is "2xx" to "5xx", or "none" when no response was received. errorType is
+// "none" for successful calls and otherwise one of "not_found", "conflict", "validation",
+// "unauthorized", "rate_limited", "client", "server", "timeout", "canceled" or "network".
+type Metrics interface {
+	ObserveRequest(operation Operation, statusClass string, errorType string, latency time.Duration)
+}
+
+// WithMetrics records every call in metrics, e.g. a MetricsRegistry.
+func WithMetrics(metrics Metrics) Option {
+	return func(form3Api *AccountApi) error {
+		if metrics == nil {
+			return fmt.Errorf("metrics must not be nil")
+		}
+		form3Api.metrics = metrics
+		return nil
+	}
+}
+
+func (form3Api AccountApi) observe(operation Operation, resp *http.Response, err error, latency time.Duration) {
+	if form3Api.metrics == nil {
+		return
+	}
+	statusClass := "none"
+	if err == nil {
+		statusClass = fmt.Sprintf("%dxx", resp.StatusCode/100)
+	}
+	form3Api.metrics.ObserveRequest(operation, statusClass, errorType(resp, err), latency)
+}
+
+func errorType(resp *http.Response, err error) string {
+	var netError net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netError) && netError.Timeout():
+		return "timeout"
+	case err != nil:
+		return "network"
+	}
+
+	switch status := resp.StatusCode; {
+	case status < http.StatusBadRequest:
+		return "none"
+	case status == http.StatusNotFound:
+		return "not_found"
+	case status == http.StatusConflict:
+		return "conflict"
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return "validation"
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return "unauthorized"
+	case status == http.StatusTooManyRequests:
+		return "rate_limited"
+	case status < http.StatusInternalServerError:
+		return "client"
+	default:
+		return "server"
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestKey struct {
+	operation   Operation
+	statusClass string
+	errorType   string
+}
+
+type latencyKey struct {
+	operation   Operation
+	statusClass string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// MetricsRegistry is the in-process Metrics implementation. It counts requests in
+// account_api_requests_total and their latency in account_api_request_duration_seconds,
+// and renders both in the Prometheus text format.
+type MetricsRegistry struct {
+	mutex     sync.Mutex
+	buckets   []float64
+	requests  map[requestKey]uint64
+	latencies map[latencyKey]*histogram
+}
+
+// NewMetricsRegistry creates a registry using buckets as latency histogram upper bounds,
+// or DefaultLatencyBuckets when none are given.
+func NewMetricsRegistry(buckets ...float64) *MetricsRegistry {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sortedBuckets := append([]float64{}, buckets...)
+	sort.Float64s(sortedBuckets)
+
+	return &MetricsRegistry{
+		buckets:   sortedBuckets,
+		requests:  make(map[requestKey]uint64),
+		latencies: make(map[latencyKey]*histogram),
+	}
+}
+
+func (registry *MetricsRegistry) ObserveRequest(operation Operation, statusClass string, errorType string, latency time.Duration) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.requests[requestKey{operation, statusClass, errorType}]++
+
+	key := latencyKey{operation, statusClass}
+	latencyHistogram, ok := registry.latencies[key]
+	if !ok {
+		latencyHistogram = &histogram{counts: make([]uint64, len(registry.buckets))}
+		registry.latencies[key] = latencyHistogram
+	}
+	seconds := latency.Seconds()
+	for i, bound := range registry.buckets {
+		if seconds <= bound {
+			latencyHistogram.counts[i]++
+		}
+	}
+	latencyHistogram.count++
+	latencyHistogram.sum += seconds
+}
+
+// RequestCount returns how many requests were recorded with the given labels.
+func (registry *MetricsRegistry) RequestCount(operation Operation, statusClass string, errorType string) uint64 {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	return registry.requests[requestKey{operation, statusClass, errorType}]
+}
+
+// WritePrometheus writes every metric in the Prometheus text exposition format.
+func (registry *MetricsRegistry) WritePrometheus(w io.Writer) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	var out strings.Builder
+	out.WriteString("# HELP account_api_requests_total Account API calls by operation, status class and error type.\n")
+	out.WriteString("# TYPE account_api_requests_total counter\n")
+	requestKeys := make([]requestKey, 0, len(registry.requests))
+	for key := range registry.requests {
+		requestKeys = append(requestKeys, key)
+	}
+	sort.Slice(requestKeys, func(i, j int) bool {
+		return fmt.Sprint(requestKeys[i]) < fmt.Sprint(requestKeys[j])
+	})
+	for _, key := range requestKeys {
+		fmt.Fprintf(&out, "account_api_requests_total{operation=%q,status_class=%q,error_type=%q} %d\n",
+			key.operation, key.statusClass, key.errorType, registry.requests[key])
+	}
+
+	out.WriteString("# HELP account_api_request_duration_seconds Latency of account API calls, including retries.\n")
+	out.WriteString("# TYPE account_api_request_duration_seconds histogram\n")
+	latencyKeys := make([]latencyKey, 0, len(registry.latencies))
+	for key := range registry.latencies {
+		latencyKeys = append(latencyKeys, key)
+	}
+	sort.Slice(latencyKeys, func(i, j int) bool {
+		return fmt.Sprint(latencyKeys[i]) < fmt.Sprint(latencyKeys[j])
+	})
+	for _, key := range latencyKeys {
+		latencyHistogram := registry.latencies[key]
+		labels := fmt.Sprintf("operation=%q,status_class=%q", key.operation, key.statusClass)
+		for i, bound := range registry.buckets {
+			fmt.Fprintf(&out, "account_api_request_duration_seconds_bucket{%s,le=%q} %d\n",
+				labels, strconv.FormatFloat(bound, 'g', -1, 64), latencyHistogram.counts[i])
+		}
+		fmt.Fprintf(&out, "account_api_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, latencyHistogram.count)
+		fmt.Fprintf(&out, "account_api_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(latencyHistogram.sum, 'g', -1, 64))
+		fmt.Fprintf(&out, "account_api_request_duration_seconds_count{%s} %d\n", labels, latencyHistogram.count)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// ServeHTTP exposes the registry as a Prometheus scrape endpoint.
+func (registry *MetricsRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = registry.WritePrometheus(w)
+}
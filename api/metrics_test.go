@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilMetrics(t *testing.T) {
+	accountApi, err := NewAccountApi(hostname, WithMetrics(nil))
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Empty(t, accountApi, "Account API is not empty")
+}
+
+func TestMetricsRecordOperations(t *testing.T) {
+	registry := NewMetricsRegistry()
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.Method {
+		case http.MethodDelete:
+			return stringResponse(409, `{"error_message": "invalid version"}`), nil
+		case http.MethodPost:
+			return nil, errors.New("connection refused")
+		}
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithMetrics(registry))
+	assert.Empty(t, err, "Error is not empty")
+
+	_ = accountApi.IsHealthy()
+	_ = accountApi.IsHealthy()
+	_ = accountApi.DeleteAccount(id, 0)
+	_, _ = accountApi.CreateAccount(getAccountData())
+
+	assert.Equal(t, uint64(2), registry.RequestCount(OperationHealth, "2xx", "none"))
+	assert.Equal(t, uint64(1), registry.RequestCount(OperationDelete, "4xx", "conflict"))
+	assert.Equal(t, uint64(1), registry.RequestCount(OperationCreate, "none", "network"))
+}
+
+func TestMetricsErrorTypes(t *testing.T) {
+	statuses := map[int]string{
+		200: "none",
+		400: "validation",
+		401: "unauthorized",
+		404: "not_found",
+		409: "conflict",
+		418: "client",
+		429: "rate_limited",
+		503: "server",
+	}
+	for status, expected := range statuses {
+		assert.Equal(t, expected, errorType(&http.Response{StatusCode: status}, nil), "Wrong error type for %d", status)
+	}
+
+	assert.Equal(t, "canceled", errorType(nil, context.Canceled))
+	assert.Equal(t, "timeout", errorType(nil, context.DeadlineExceeded))
+	assert.Equal(t, "network", errorType(nil, errors.New("connection refused")))
+}
+
+func TestMetricsPrometheusFormat(t *testing.T) {
+	registry := NewMetricsRegistry(0.1, 1)
+	registry.ObserveRequest(OperationGet, "2xx", "none", 50*time.Millisecond)
+	registry.ObserveRequest(OperationGet, "2xx", "none", 500*time.Millisecond)
+	registry.ObserveRequest(OperationGet, "4xx", "not_found", 20*time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	output := recorder.Body.String()
+
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain"))
+	assert.Contains(t, output, "# TYPE account_api_requests_total counter\n")
+	assert.Contains(t, output, `account_api_requests_total{operation="get",status_class="2xx",error_type="none"} 2`)
+	assert.Contains(t, output, `account_api_requests_total{operation="get",status_class="4xx",error_type="not_found"} 1`)
+	assert.Contains(t, output, "# TYPE account_api_request_duration_seconds histogram\n")
+	assert.Contains(t, output, `account_api_request_duration_seconds_bucket{operation="get",status_class="2xx",le="0.1"} 1`)
+	assert.Contains(t, output, `account_api_request_duration_seconds_bucket{operation="get",status_class="2xx",le="1"} 2`)
+	assert.Contains(t, output, `account_api_request_duration_seconds_bucket{operation="get",status_class="2xx",le="+Inf"} 2`)
+	assert.Contains(t, output, `account_api_request_duration_seconds_sum{operation="get",status_class="2xx"} 0.55`)
+	assert.Contains(t, output, `account_api_request_duration_seconds_count{operation="get",status_class="2xx"} 2`)
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultRefreshBefore = time.Minute
+const defaultTokenTimeout = 30 * time.Second
+
+// ClientCredentials configures a ClientCredentialsTokenSource.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// RefreshBefore is how long before expiry a token is refreshed in the background while
+	// still being used. It defaults to a minute and is capped at half the token lifetime.
+	// A failed background refresh is retried after a quarter of it, or sooner close to expiry.
+	RefreshBefore time.Duration
+	// HTTPClient sends the token requests. It defaults to a client with a 30 second timeout.
+	HTTPClient *http.Client
+}
+
+// ClientCredentialsTokenSource obtains bearer tokens with the OAuth2 client credentials
+// grant. Tokens are cached until they expire and fetched at most once at a time, however
+// many goroutines ask for them. It is a RefreshableAuthenticator, so AccountApi retries a
+// request rejected with 401 once with a new token.
+type ClientCredentialsTokenSource struct {
+	credentials ClientCredentials
+	now         func() time.Time
+
+	mutex     sync.Mutex
+	token     string
+	refreshAt time.Time
+	expiry    time.Time
+	fetching  *tokenFetch
+}
+
+// tokenFetch is a token request in flight. done is closed once err is set.
+type tokenFetch struct {
+	done chan struct{}
+	err  error
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type tokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func NewClientCredentialsTokenSource(credentials ClientCredentials) (*ClientCredentialsTokenSource, error) {
+	if _, err := validUrl(credentials.TokenURL); err != nil {
+		return nil, fmt.Errorf("invalid token url. Error: %w", err)
+	}
+	if credentials.ClientID == "" {
+		return nil, fmt.Errorf("client id must not be empty")
+	}
+	if credentials.RefreshBefore < 0 {
+		return nil, fmt.Errorf("refresh before must not be negative, got %s", credentials.RefreshBefore)
+	}
+	if credentials.RefreshBefore == 0 {
+		credentials.RefreshBefore = defaultRefreshBefore
+	}
+	if credentials.HTTPClient == nil {
+		credentials.HTTPClient = &http.Client{Timeout: defaultTokenTimeout}
+	}
+
+	return &ClientCredentialsTokenSource{credentials: credentials, now: time.Now}, nil
+}
+
+func (source *ClientCredentialsTokenSource) Authenticate(req *http.Request) error {
+	token, err := source.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token if req was sent with it, so the next call fetches a new one.
+func (source *ClientCredentialsTokenSource) Invalidate(req *http.Request) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	if source.token != "" && req.Header.Get("Authorization") == "Bearer "+source.token {
+		source.token = ""
+	}
+}
+
+// Token returns the cached token, fetching a new one when there is none or it expired.
+// A token close to expiry is still returned while a new one is fetched in the background.
+func (source *ClientCredentialsTokenSource) Token(ctx context.Context) (string, error) {
+	for {
+		source.mutex.Lock()
+		now := source.now()
+		if source.token != "" && now.Before(source.expiry) {
+			if !now.Before(source.refreshAt) && source.fetching == nil {
+				source.startFetch()
+			}
+			token := source.token
+			source.mutex.Unlock()
+			return token, nil
+		}
+		if source.fetching == nil {
+			source.startFetch()
+		}
+		fetch := source.fetching
+		source.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-fetch.done:
+		}
+		if fetch.err != nil {
+			return "", fetch.err
+		}
+	}
+}
+
+// startFetch requests a new token in the background. The request is not bound to the
+// context of any caller, since every waiting caller shares it. The mutex must be held.
+func (source *ClientCredentialsTokenSource) startFetch() {
+	fetch := &tokenFetch{done: make(chan struct{})}
+	source.fetching = fetch
+
+	go func() {
+		requested := source.now()
+		token, err := source.fetchToken(context.Background())
+
+		source.mutex.Lock()
+		defer source.mutex.Unlock()
+		if err == nil {
+			source.token = token.AccessToken
+			source.expiry, source.refreshAt = source.lifetime(requested, token.ExpiresIn)
+		} else if source.token != "" {
+			source.refreshAt = source.retryAt(source.now())
+		}
+		fetch.err = err
+		source.fetching = nil
+		close(fetch.done)
+	}()
+}
+
+// lifetime returns when a token obtained at requested expires and when to refresh it. A
+// token without expires_in is kept until the API rejects it.
+func (source *ClientCredentialsTokenSource) lifetime(requested time.Time, expiresIn int64) (time.Time, time.Time) {
+	if expiresIn <= 0 {
+		never := requested.AddDate(100, 0, 0)
+		return never, never
+	}
+	validity := time.Duration(expiresIn) * time.Second
+	refreshBefore := source.credentials.RefreshBefore
+	if refreshBefore > validity/2 {
+		refreshBefore = validity / 2
+	}
+	expiry := requested.Add(validity)
+	return expiry, expiry.Add(-refreshBefore)
+}
+
+// retryAt returns when to retry a failed background refresh of a token that is still
+// valid, so callers in the refresh window do not each start a new token request.
+func (source *ClientCredentialsTokenSource) retryAt(now time.Time) time.Time {
+	backoff := source.credentials.RefreshBefore / 4
+	if remaining := source.expiry.Sub(now) / 2; remaining < backoff {
+		backoff = remaining
+	}
+	return now.Add(backoff)
+}
+
+func (source *ClientCredentialsTokenSource) fetchToken(ctx context.Context) (*tokenResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTokenTimeout)
+	defer cancel()
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(source.credentials.Scopes) > 0 {
+		form.Set("scope", strings.Join(source.credentials.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, source.credentials.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error creating token request. Error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", applicationJsonContentType)
+	req.SetBasicAuth(url.QueryEscape(source.credentials.ClientID), url.QueryEscape(source.credentials.ClientSecret))
+
+	resp, err := source.credentials.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching token. Error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading token response. Error: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var tokenError tokenErrorResponse
+		if json.Unmarshal(body, &tokenError) == nil && tokenError.Error != "" {
+			return nil, fmt.Errorf("token request failed with status %d: %s %s", resp.StatusCode, tokenError.Error, tokenError.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err = json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("unable to parse token response. Error: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access token")
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token type %s", token.TokenType)
+	}
+	return &token, nil
+}
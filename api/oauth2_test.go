@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenServer struct {
+	*httptest.Server
+	requests    int32
+	expiresIn   int
+	release     chan struct{}
+	unavailable int32
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	server := &tokenServer{expiresIn: expiresIn}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if r.Method != http.MethodPost || clientID != "client" || clientSecret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_client", "error_description": "unknown client"}`)
+			return
+		}
+		if server.release != nil {
+			<-server.release
+		}
+		count := atomic.AddInt32(&server.requests, 1)
+		if atomic.LoadInt32(&server.unavailable) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, count, server.expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *tokenServer) tokenSource(t *testing.T) *ClientCredentialsTokenSource {
+	source, err := NewClientCredentialsTokenSource(ClientCredentials{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"accounts:read", "accounts:write"},
+	})
+	assert.Empty(t, err, "Error is not empty")
+	return source
+}
+
+func TestTokenIsCachedUntilExpiry(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	source := server.tokenSource(t)
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	token, err := source.Token(context.Background())
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "token-1", token)
+	token, _ = source.Token(context.Background())
+	assert.Equal(t, "token-1", token)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+
+	now = now.Add(2 * time.Hour)
+	token, _ = source.Token(context.Background())
+	assert.Equal(t, "token-2", token)
+}
+
+func TestTokenIsRefreshedProactively(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	source := server.tokenSource(t)
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	_, _ = source.Token(context.Background())
+	now = now.Add(3600*time.Second - 30*time.Second)
+
+	token, err := source.Token(context.Background())
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "token-1", token, "Token close to expiry is not used while refreshing")
+	assert.Eventually(t, func() bool {
+		token, _ = source.Token(context.Background())
+		return token == "token-2"
+	}, time.Second, time.Millisecond)
+}
+
+func TestFailedRefreshIsRetriedAfterBackoff(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	source := server.tokenSource(t)
+	now := time.Now()
+	source.now = func() time.Time { return now }
+
+	_, _ = source.Token(context.Background())
+	atomic.StoreInt32(&server.unavailable, 1)
+	now = now.Add(3600*time.Second - 30*time.Second)
+
+	token, err := source.Token(context.Background())
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "token-1", token)
+	assert.Eventually(t, func() bool {
+		source.mutex.Lock()
+		defer source.mutex.Unlock()
+		return source.fetching == nil
+	}, time.Second, time.Millisecond)
+
+	for i := 0; i < 10; i++ {
+		token, _ = source.Token(context.Background())
+		assert.Equal(t, "token-1", token)
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.requests), "Failed refresh is retried by every call")
+
+	atomic.StoreInt32(&server.unavailable, 0)
+	now = now.Add(15 * time.Second)
+	assert.Eventually(t, func() bool {
+		token, _ = source.Token(context.Background())
+		return token == "token-3"
+	}, time.Second, time.Millisecond)
+}
+
+func TestTokenIsFetchedOnceConcurrently(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	server.release = make(chan struct{})
+	source := server.tokenSource(t)
+
+	var wait sync.WaitGroup
+	tokens := make([]string, 20)
+	for i := range tokens {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			tokens[i], _ = source.Token(context.Background())
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(server.release)
+	wait.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+	for _, token := range tokens {
+		assert.Equal(t, "token-1", token)
+	}
+}
+
+func TestTokenWaitRespectsContext(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	server.release = make(chan struct{})
+	defer close(server.release)
+	source := server.tokenSource(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := source.Token(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error is not a deadline exceeded")
+}
+
+func TestTokenErrorResponse(t *testing.T) {
+	server := newTokenServer(t, 3600)
+	source, _ := NewClientCredentialsTokenSource(ClientCredentials{TokenURL: server.URL, ClientID: "client", ClientSecret: "wrong"})
+
+	_, err := source.Token(context.Background())
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Contains(t, err.Error(), "invalid_client")
+}
+
+func TestInvalidClientCredentials(t *testing.T) {
+	invalidCredentials := []ClientCredentials{
+		{TokenURL: "", ClientID: "client"},
+		{TokenURL: "http://localhost:8080/token", ClientID: ""},
+		{TokenURL: "http://localhost:8080/token", ClientID: "client", RefreshBefore: -time.Second},
+	}
+
+	for _, credentials := range invalidCredentials {
+		source, err := NewClientCredentialsTokenSource(credentials)
+		assert.NotEmpty(t, err, "Error is empty")
+		assert.Empty(t, source, "Token source is not empty")
+	}
+}
+
+func TestUnauthorizedRequestIsRetriedWithNewToken(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	var authorizations []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		authorizations = append(authorizations, req.Header.Get("Authorization"))
+		if req.Header.Get("Authorization") == "Bearer token-1" {
+			return stringResponse(401, `{"error_message": "token revoked"}`), nil
+		}
+		return stringResponse(201, `{"data": {}}`), nil
+	})
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithAuthenticator(tokens.tokenSource(t)))
+	assert.Empty(t, err, "Error is not empty")
+
+	_, err = accountApi.CreateAccount(getAccountData())
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+}
+
+func TestUnauthorizedRequestIsRetriedOnlyOnce(t *testing.T) {
+	tokens := newTokenServer(t, 3600)
+	var requests int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return stringResponse(401, `{"error_message": "forbidden"}`), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithAuthenticator(tokens.tokenSource(t)))
+
+	err := accountApi.IsHealthy()
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError), "Error is not an APIError")
+	assert.Equal(t, 401, apiError.StatusCode)
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Option configures an AccountApi created by NewAccountApi.
+type Option func(*AccountApi) error
+
+// WithHTTPClient makes the AccountApi send every request through client instead of
+// http.DefaultClient. The client is copied, so WithTimeout and WithTransport, in any
+// order, only change the copy.
+func WithHTTPClient(client *http.Client) Option {
+	return func(form3Api *AccountApi) error {
+		if client == nil {
+			return fmt.Errorf("http client must not be nil")
+		}
+		clientCopy := *client
+		form3Api.httpClient = &clientCopy
+		return nil
+	}
+}
+
+// WithTimeout sets the overall time limit of a single request, including reading the response body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(form3Api *AccountApi) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative, got %s", timeout)
+		}
+		form3Api.timeout = &timeout
+		return nil
+	}
+}
+
+// WithTransport sets the RoundTripper used to send requests, e.g. an *http.Transport with
+// a custom proxy or TLS configuration.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(form3Api *AccountApi) error {
+		if transport == nil {
+			return fmt.Errorf("transport must not be nil")
+		}
+		form3Api.transport = transport
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(form3Api *AccountApi) error {
+		if userAgent == "" {
+			return fmt.Errorf("user agent must not be empty")
+		}
+		form3Api.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request. It may be used several times.
+func WithHeader(key string, value string) Option {
+	return func(form3Api *AccountApi) error {
+		if key == "" {
+			return fmt.Errorf("header key must not be empty")
+		}
+		form3Api.headers.Add(key, value)
+		return nil
+	}
+}
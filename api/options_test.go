@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func stringResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	invalidOptions := []Option{
+		WithHTTPClient(nil),
+		WithTimeout(-time.Second),
+		WithTransport(nil),
+		WithUserAgent(""),
+		WithHeader("", "value"),
+	}
+
+	for _, option := range invalidOptions {
+		accountApi, err := NewAccountApi(hostname, option)
+		assert.NotEmpty(t, err, "Error is empty")
+		assert.Empty(t, accountApi, "Account API is not empty")
+	}
+}
+
+func TestTransportAndHeadersOptions(t *testing.T) {
+	var requests []*http.Request
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+
+	accountApi, err := NewAccountApi(hostname,
+		WithTransport(transport),
+		WithUserAgent("accounts-client/1.0"),
+		WithHeader("X-Tenant", "form3"))
+	assert.Empty(t, err, "Error is not empty")
+
+	err = accountApi.IsHealthy()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, "accounts-client/1.0", requests[0].Header.Get("User-Agent"))
+	assert.Equal(t, "form3", requests[0].Header.Get("X-Tenant"))
+	assert.Equal(t, fmt.Sprintf("%s/v1/health", hostname), requests[0].URL.String())
+}
+
+func TestHTTPClientOptionIsNotModified(t *testing.T) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return stringResponse(204, ``), nil
+	})
+	client := &http.Client{Transport: transport}
+
+	accountApi, err := NewAccountApi(hostname, WithHTTPClient(client), WithTimeout(time.Second))
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, time.Duration(0), client.Timeout, "Shared client was modified")
+
+	err = accountApi.DeleteAccount("0d209d7f-d07a-4542-947f-5885fddddae7", 0)
+	assert.Empty(t, err, "Error is not empty")
+}
+
+func TestClientOptionsInAnyOrder(t *testing.T) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+	client := &http.Client{Timeout: time.Minute}
+
+	accountApi, err := NewAccountApi(hostname, WithTimeout(5*time.Second), WithTransport(transport), WithHTTPClient(client))
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 5*time.Second, accountApi.httpClient.Timeout)
+	assert.Equal(t, time.Minute, client.Timeout, "Shared client was modified")
+	assert.Empty(t, accountApi.IsHealthy(), "Error is not empty")
+}
+
+func TestTimeoutOption(t *testing.T) {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithTimeout(20*time.Millisecond))
+	assert.Empty(t, err, "Error is not empty")
+
+	_, err = accountApi.GetAccount("0d209d7f-d07a-4542-947f-5885fddddae7")
+	assert.NotEmpty(t, err, "Error is empty")
+	var urlErr interface{ Timeout() bool }
+	assert.True(t, errors.As(err, &urlErr) && urlErr.Timeout(), "Error is not a timeout: %v", err)
+}
@@ -0,0 +1,20 @@
+package api
+
+import "form3-interview-accounts/model"
+
+func pageQueryParameters(filters map[string]string, page model.PageRequest) map[string]string {
+	pageParameters := page.QueryParameters()
+	if len(pageParameters) == 0 {
+		return filters
+	}
+
+	queryParameters := make(map[string]string, len(filters)+len(pageParameters))
+	for key, value := range filters {
+		queryParameters[key] = value
+	}
+	for key, value := range pageParameters {
+		queryParameters[key] = value
+	}
+
+	return queryParameters
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"form3-interview-accounts/model"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func accountsPageResponder(pages map[string]string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		number := req.URL.Query().Get("page[number]")
+		if number == "" {
+			number = "0"
+		}
+		body, ok := pages[number]
+		if !ok {
+			return httpmock.NewStringResponse(404, ``), nil
+		}
+		return httpmock.NewStringResponse(200, body), nil
+	}
+}
+
+func TestGetAccountsPage(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponderWithQuery("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname), "page[number]=1&page[size]=2",
+		httpmock.NewStringResponder(200, `{"data": [{"id": "0d209d7f-d07a-4542-947f-5885fddddae9", "type": "accounts", "version": 0}],
+			"links": {"first": "/v1/organisation/accounts?page%5Bnumber%5D=first&page%5Bsize%5D=2",
+				"last": "/v1/organisation/accounts?page%5Bnumber%5D=last&page%5Bsize%5D=2",
+				"prev": "/v1/organisation/accounts?page%5Bnumber%5D=0&page%5Bsize%5D=2",
+				"self": "/v1/organisation/accounts?page%5Bnumber%5D=1&page%5Bsize%5D=2"}}`))
+
+	data, err := accountApi.GetAccountsPage(model.AccountFilter{}, model.PageRequest{Number: 1, Size: 2})
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 1, len(data.Data))
+	assert.NotEmpty(t, data.Links, "Links are empty")
+	assert.Equal(t, "/v1/organisation/accounts?page%5Bnumber%5D=0&page%5Bsize%5D=2", data.Links.Prev)
+	assert.Empty(t, data.Links.Next)
+}
+
+func TestListAccountsFollowsNextLinks(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		accountsPageResponder(map[string]string{
+			"0": `{"data": [{"id": "1"}, {"id": "2"}], "links": {"next": "/v1/organisation/accounts?filter%5Bcountry%5D=GB&page%5Bnumber%5D=1&page%5Bsize%5D=2"}}`,
+			"1": `{"data": [{"id": "3"}, {"id": "4"}], "links": {"next": "/v1/organisation/accounts?filter%5Bcountry%5D=GB&page%5Bnumber%5D=2&page%5Bsize%5D=2"}}`,
+			"2": `{"data": [{"id": "5"}], "links": {}}`,
+		}))
+
+	iterator := accountApi.ListAccounts(context.Background(), model.AccountFilter{Country: "GB"}, 2)
+	var ids []string
+	for iterator.Next() {
+		ids = append(ids, iterator.Account().ID)
+	}
+	assert.Empty(t, iterator.Err(), "Error is not empty")
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+	assert.False(t, iterator.Next(), "Iterator restarted after the last page")
+}
+
+func TestListAccountsStopsOnEmptyPage(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		accountsPageResponder(map[string]string{
+			"0": `{"data": [], "links": {"next": "/v1/organisation/accounts?page%5Bnumber%5D=1"}}`,
+		}))
+
+	iterator := accountApi.ListAccounts(context.Background(), model.AccountFilter{}, 0)
+	assert.False(t, iterator.Next(), "Iterator returned an account")
+	assert.Empty(t, iterator.Err(), "Error is not empty")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestListAccountsReportsErrors(t *testing.T) {
+	accountApi, _ := getAccountApi()
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		accountsPageResponder(map[string]string{
+			"0": `{"data": [{"id": "1"}], "links": {"next": "/v1/organisation/accounts?page%5Bnumber%5D=1&page%5Bsize%5D=1"}}`,
+		}))
+
+	iterator := accountApi.ListAccounts(context.Background(), model.AccountFilter{}, 1)
+	assert.True(t, iterator.Next())
+	assert.Equal(t, "1", iterator.Account().ID)
+	assert.False(t, iterator.Next())
+	assert.True(t, IsNotFound(iterator.Err()))
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Reset header values above this are unix timestamps rather than delays in seconds.
+const epochResetThreshold = 1_000_000_000
+
+// RateLimit configures a RateLimiter.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained rate at which requests are sent.
+	RequestsPerSecond float64
+	// Burst is how many requests can be sent at once after a quiet period.
+	Burst int
+	// Adaptive halves the rate on every 429 Too Many Requests, down to a tenth of
+	// RequestsPerSecond, and recovers it gradually on other responses. It also pauses
+	// requests for the Retry-After delay and until the reset of an exhausted
+	// RateLimit-Remaining or X-RateLimit-Remaining quota.
+	Adaptive bool
+}
+
+// RateLimiter is a token bucket limiting the requests of one or more AccountApi. Requests
+// wait for a token before being sent, including retries.
+type RateLimiter struct {
+	limit   RateLimit
+	minRate float64
+	now     func() time.Time
+
+	mutex        sync.Mutex
+	rate         float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func NewRateLimiter(limit RateLimit) (*RateLimiter, error) {
+	if limit.RequestsPerSecond <= 0 || math.IsInf(limit.RequestsPerSecond, 0) || math.IsNaN(limit.RequestsPerSecond) {
+		return nil, fmt.Errorf("requests per second must be positive, got %v", limit.RequestsPerSecond)
+	}
+	if limit.Burst < 1 {
+		return nil, fmt.Errorf("burst must be at least 1, got %d", limit.Burst)
+	}
+
+	now := time.Now
+	return &RateLimiter{
+		limit:   limit,
+		minRate: limit.RequestsPerSecond / 10,
+		now:     now,
+		rate:    limit.RequestsPerSecond,
+		tokens:  float64(limit.Burst),
+		last:    now(),
+	}, nil
+}
+
+// WithRateLimiter makes every request wait for limiter, which may be shared by several AccountApi.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(form3Api *AccountApi) error {
+		if limiter == nil {
+			return fmt.Errorf("rate limiter must not be nil")
+		}
+		form3Api.rateLimiter = limiter
+		return nil
+	}
+}
+
+// Rate returns the current rate in requests per second, lower than the configured one
+// while an adaptive limiter backs off.
+func (limiter *RateLimiter) Rate() float64 {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	return limiter.rate
+}
+
+// Wait blocks until a request may be sent. It fails straight away when ctx would be done
+// before then, and with the context error when ctx is done while waiting.
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	now := limiter.now()
+	wait := limiter.reserve(now)
+	if wait <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(wait)) {
+		limiter.cancel()
+		return rateLimitDeadlineError{wait: wait}
+	}
+	if err := sleep(ctx, wait); err != nil {
+		limiter.cancel()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token and returns how long to wait until it may be used. Tokens go
+// negative while requests queue up, so waiters are served in order.
+func (limiter *RateLimiter) reserve(now time.Time) time.Duration {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	limiter.refill(now)
+	limiter.tokens--
+
+	var wait time.Duration
+	if limiter.tokens < 0 {
+		wait = time.Duration(-limiter.tokens / limiter.rate * float64(time.Second))
+	}
+	if blocked := limiter.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait
+}
+
+// cancel gives back a reserved token that was not used.
+func (limiter *RateLimiter) cancel() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.tokens = math.Min(limiter.tokens+1, float64(limiter.limit.Burst))
+}
+
+func (limiter *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(limiter.last); elapsed > 0 {
+		limiter.tokens = math.Min(limiter.tokens+elapsed.Seconds()*limiter.rate, float64(limiter.limit.Burst))
+		limiter.last = now
+	}
+}
+
+// rateLimitDeadlineError reports a wait longer than the context allows. It matches
+// context.DeadlineExceeded and is not retried, as the retry would fail the same way.
+type rateLimitDeadlineError struct {
+	wait time.Duration
+}
+
+func (err rateLimitDeadlineError) Error() string {
+	return fmt.Sprintf("rate limit wait of %s exceeds the context deadline", err.wait)
+}
+
+func (err rateLimitDeadlineError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// observe adapts an adaptive limiter to the response of a request.
+func (limiter *RateLimiter) observe(resp *http.Response) {
+	if !limiter.limit.Adaptive || resp == nil {
+		return
+	}
+
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	now := limiter.now()
+	limiter.refill(now)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		limiter.rate = math.Max(limiter.rate/2, limiter.minRate)
+		limiter.tokens = math.Min(limiter.tokens, 0)
+		if wait, ok := retryAfter(resp, now); ok {
+			limiter.block(now.Add(wait))
+		}
+	} else {
+		limiter.rate = math.Min(limiter.rate+limiter.limit.RequestsPerSecond/10, limiter.limit.RequestsPerSecond)
+	}
+
+	if reset, ok := quotaReset(resp.Header, now); ok {
+		limiter.block(reset)
+	}
+}
+
+func (limiter *RateLimiter) block(until time.Time) {
+	if until.After(limiter.blockedUntil) {
+		limiter.blockedUntil = until
+	}
+}
+
+// quotaReset returns when an exhausted quota resets, from either the RateLimit-* headers
+// of the IETF draft or the common X-RateLimit-* ones.
+func quotaReset(header http.Header, now time.Time) (time.Time, bool) {
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+		if err != nil || remaining > 0 {
+			continue
+		}
+		reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+		if err != nil || reset < 0 {
+			continue
+		}
+		if reset > epochResetThreshold {
+			return time.Unix(reset, 0), true
+		}
+		return now.Add(time.Duration(reset) * time.Second), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getRateLimiter(t *testing.T, limit RateLimit) (*RateLimiter, *time.Time) {
+	limiter, err := NewRateLimiter(limit)
+	assert.Empty(t, err, "Error is not empty")
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	limiter.last = now
+	return limiter, &now
+}
+
+func TestInvalidRateLimit(t *testing.T) {
+	invalidLimits := []RateLimit{
+		{RequestsPerSecond: 0, Burst: 1},
+		{RequestsPerSecond: -1, Burst: 1},
+		{RequestsPerSecond: 10, Burst: 0},
+	}
+
+	for _, limit := range invalidLimits {
+		limiter, err := NewRateLimiter(limit)
+		assert.NotEmpty(t, err, "Error is empty")
+		assert.Empty(t, limiter, "Rate limiter is not empty")
+	}
+}
+
+func TestNilRateLimiter(t *testing.T) {
+	accountApi, err := NewAccountApi(hostname, WithRateLimiter(nil))
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Empty(t, accountApi, "Account API is not empty")
+}
+
+func TestTokenBucket(t *testing.T) {
+	limiter, now := getRateLimiter(t, RateLimit{RequestsPerSecond: 10, Burst: 2})
+
+	assert.Equal(t, time.Duration(0), limiter.reserve(*now))
+	assert.Equal(t, time.Duration(0), limiter.reserve(*now))
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(*now))
+	assert.Equal(t, 200*time.Millisecond, limiter.reserve(*now))
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), limiter.reserve(*now))
+	assert.Equal(t, time.Duration(0), limiter.reserve(*now), "Burst is not refilled")
+	assert.Equal(t, 100*time.Millisecond, limiter.reserve(*now))
+}
+
+func TestRateLimiterWaitRespectsContext(t *testing.T) {
+	limiter, _ := NewRateLimiter(RateLimit{RequestsPerSecond: 1, Burst: 1})
+	assert.Empty(t, limiter.Wait(context.Background()), "Error is not empty")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error is not a deadline exceeded")
+	assert.Less(t, time.Since(start), 10*time.Millisecond, "Wait did not fail fast")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	err = limiter.Wait(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), "Error is not a cancellation")
+}
+
+func TestAdaptiveRateLimiter(t *testing.T) {
+	limiter, now := getRateLimiter(t, RateLimit{RequestsPerSecond: 10, Burst: 5, Adaptive: true})
+
+	tooManyRequests := stringResponse(429, "")
+	tooManyRequests.Header.Set("Retry-After", "2")
+	limiter.observe(tooManyRequests)
+	assert.Equal(t, 5.0, limiter.Rate())
+	assert.Equal(t, 2*time.Second, limiter.reserve(*now))
+
+	for i := 0; i < 10; i++ {
+		limiter.observe(stringResponse(429, ""))
+	}
+	assert.Equal(t, 1.0, limiter.Rate(), "Rate is not floored at a tenth")
+
+	limiter.observe(stringResponse(200, ""))
+	assert.Equal(t, 2.0, limiter.Rate())
+	for i := 0; i < 20; i++ {
+		limiter.observe(stringResponse(200, ""))
+	}
+	assert.Equal(t, 10.0, limiter.Rate(), "Rate is not recovered")
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	limiter, now := getRateLimiter(t, RateLimit{RequestsPerSecond: 10, Burst: 5, Adaptive: true})
+
+	resp := stringResponse(200, "")
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(3*time.Second).Unix(), 10))
+	limiter.observe(resp)
+	wait := limiter.reserve(*now)
+	assert.True(t, wait > 2*time.Second && wait <= 3*time.Second, "Unexpected wait %s", wait)
+
+	limiter, now = getRateLimiter(t, RateLimit{RequestsPerSecond: 10, Burst: 5, Adaptive: true})
+	resp = stringResponse(200, "")
+	resp.Header.Set("RateLimit-Remaining", "0")
+	resp.Header.Set("RateLimit-Reset", "4")
+	limiter.observe(resp)
+	assert.Equal(t, 4*time.Second, limiter.reserve(*now))
+
+	limiter, now = getRateLimiter(t, RateLimit{RequestsPerSecond: 10, Burst: 5})
+	limiter.observe(resp)
+	assert.Equal(t, time.Duration(0), limiter.reserve(*now), "Non adaptive limiter reads headers")
+}
+
+func TestRateLimiterAppliedToRequests(t *testing.T) {
+	var requests int
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+	limiter, _ := NewRateLimiter(RateLimit{RequestsPerSecond: 100, Burst: 1})
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithRateLimiter(limiter), WithRetryPolicy(fastRetryPolicy()))
+	assert.Empty(t, err, "Error is not empty")
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		assert.Empty(t, accountApi.IsHealthy(), "Error is not empty")
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond, "Requests were not limited")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_ = accountApi.IsHealthyWithContext(context.Background())
+	err = accountApi.IsHealthyWithContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error is not a deadline exceeded")
+	assert.Equal(t, 5, requests, "Request exceeding the deadline was sent or retried")
+}
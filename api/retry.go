@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how idempotent requests are retried after connection errors,
+// 429 Too Many Requests and 5xx responses. A Retry-After header sent by the API takes
+// precedence over the computed backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. It must be at
+	// least 1, which disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed wait between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt.
+	Multiplier float64
+	// Jitter randomises every wait by up to this fraction of it, between 0 and 1.
+	Jitter float64
+	// MaxElapsedTime is the total time budget for all attempts. Zero means no budget.
+	MaxElapsedTime time.Duration
+}
+
+// DefaultRetryPolicy returns a policy suited to an account API restarting behind docker-compose.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsedTime: 30 * time.Second,
+	}
+}
+
+// WithRetryPolicy enables retries of GET, DELETE and health check requests, and of
+// account creation when the account ID is supplied by the client.
+// A retried creation answered with 409 Conflict returns the account created by an
+// earlier attempt, when it matches the requested one, and a retried deletion answered
+// with 404 Not Found succeeds.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(form3Api *AccountApi) error {
+		if policy.MaxAttempts < 1 {
+			return fmt.Errorf("retry policy max attempts must be at least 1, got %d", policy.MaxAttempts)
+		}
+		if policy.InitialBackoff < 0 || policy.MaxBackoff < 0 || policy.MaxElapsedTime < 0 {
+			return fmt.Errorf("retry policy durations must not be negative")
+		}
+		if policy.Multiplier != 0 && policy.Multiplier < 1 {
+			return fmt.Errorf("retry policy multiplier must be at least 1, got %f", policy.Multiplier)
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return fmt.Errorf("retry policy jitter must be between 0 and 1, got %f", policy.Jitter)
+		}
+		form3Api.retryPolicy = policy
+		return nil
+	}
+}
+
+var jitterRandom = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	wait := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxBackoff > 0 && wait > float64(policy.MaxBackoff) {
+		wait = float64(policy.MaxBackoff)
+	}
+
+	if policy.Jitter > 0 {
+		jitterRandom.Lock()
+		wait += wait * policy.Jitter * (2*jitterRandom.Float64() - 1)
+		jitterRandom.Unlock()
+	}
+
+	return time.Duration(wait)
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		var deadlineError rateLimitDeadlineError
+		return ctx.Err() == nil && !errors.As(err, &deadlineError)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented)
+}
+
+// retryAfter parses a Retry-After header holding either delay seconds or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func discardBody(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
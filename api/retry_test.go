@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3-interview-accounts/model"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func getRetryingAccountApi(policy RetryPolicy) (*AccountApi, error) {
+	return NewAccountApi(hostname, WithRetryPolicy(policy))
+}
+
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+func TestInvalidRetryPolicy(t *testing.T) {
+	policies := []RetryPolicy{
+		{MaxAttempts: 0, InitialBackoff: time.Millisecond},
+		{MaxAttempts: -1},
+		{MaxAttempts: 3, InitialBackoff: -time.Second},
+		{MaxAttempts: 3, Multiplier: 0.5},
+		{MaxAttempts: 3, Jitter: 2},
+	}
+
+	for _, policy := range policies {
+		accountApi, err := getRetryingAccountApi(policy)
+		assert.NotEmpty(t, err, "Error is empty")
+		assert.Empty(t, accountApi, "Account API is not empty")
+	}
+}
+
+func TestRetryOnServerErrors(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/health", hostname),
+		httpmock.NewStringResponder(503, `{"error_message": "service unavailable"}`).Once().
+			Then(httpmock.NewStringResponder(429, ``).Once()).
+			Then(httpmock.NewStringResponder(200, `{"status": "up"}`)))
+
+	err := accountApi.IsHealthy()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestRetryOnConnectionErrors(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/v1/organisation/accounts/%s?version=0", hostname, id),
+		httpmock.NewErrorResponder(errors.New("connection refused")).Once().
+			Then(httpmock.NewStringResponder(204, ``)))
+
+	err := accountApi.DeleteAccount(id, 0)
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		httpmock.NewStringResponder(500, `{"error_message": "internal error"}`))
+
+	_, err := accountApi.GetAccounts(model.AccountFilter{})
+	var apiError *APIError
+	assert.True(t, errors.As(err, &apiError), "Error is not an APIError")
+	assert.Equal(t, 500, apiError.StatusCode)
+	assert.Equal(t, "internal error", apiError.ErrorMessage)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/organisation/accounts/%s", hostname, id),
+		httpmock.NewStringResponder(404, ``))
+
+	_, err := accountApi.GetAccount(id)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestCreateAccountRetriesOnlyWithClientSuppliedId(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	var bodies []string
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			return httpmock.NewStringResponse(503, ``), nil
+		})
+
+	accountData := getAccountData()
+	_, err := accountApi.CreateAccount(accountData)
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Equal(t, 3, len(bodies))
+	assert.Equal(t, bodies[0], bodies[2], "Retried request body differs")
+
+	accountData.Data.ID = ""
+	_, err = accountApi.CreateAccount(accountData)
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Equal(t, 4, len(bodies))
+}
+
+func TestRetriedCreateConflictReturnsCreatedAccount(t *testing.T) {
+	accountData := getAccountData()
+	stored, _ := json.Marshal(accountData)
+	storedClosed := strings.Replace(string(stored), `"attributes":{`, `"attributes":{"status":"closed",`, 1)
+	other := getAccountData()
+	other.Data.OrganisationID = "4a5b6c7d-d07a-4542-947f-5885fddddae7"
+	storedOther, _ := json.Marshal(other)
+	accountUrl := fmt.Sprintf("%s/v1/organisation/accounts/%s", hostname, accountData.Data.ID)
+
+	for _, test := range []struct {
+		stored   string
+		conflict bool
+	}{{string(stored), false}, {storedClosed, false}, {string(storedOther), true}} {
+		accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+		httpmock.Activate()
+		httpmock.RegisterResponder("POST", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+			httpmock.NewStringResponder(503, ``).Once().
+				Then(httpmock.NewStringResponder(409, `{"error_message": "Account cannot be created as it violates a duplicate constraint"}`)))
+		httpmock.RegisterResponder("GET", accountUrl, httpmock.NewStringResponder(200, test.stored))
+
+		account, err := accountApi.CreateAccount(accountData)
+		if test.conflict {
+			assert.True(t, IsConflict(err), "Error is not a conflict")
+		} else {
+			assert.Empty(t, err, "Error is not empty")
+			assert.Equal(t, accountData.Data.ID, account.ID)
+		}
+		assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+accountUrl])
+		httpmock.DeactivateAndReset()
+	}
+}
+
+func TestFirstCreateConflictIsNotResolved(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/v1/organisation/accounts", hostname),
+		httpmock.NewStringResponder(409, `{"error_message": "Account cannot be created as it violates a duplicate constraint"}`))
+
+	_, err := accountApi.CreateAccount(getAccountData())
+	assert.True(t, IsConflict(err), "Error is not a conflict")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetriedDeleteNotFoundIsSuccess(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	id := getAccountData().Data.ID
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/v1/organisation/accounts/%s?version=0", hostname, id),
+		httpmock.NewStringResponder(503, ``).Once().
+			Then(httpmock.NewStringResponder(404, ``)))
+
+	err := accountApi.DeleteAccount(id, 0)
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestFirstDeleteNotFoundIsAnError(t *testing.T) {
+	accountApi, _ := getRetryingAccountApi(fastRetryPolicy())
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	id := getAccountData().Data.ID
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/v1/organisation/accounts/%s?version=0", hostname, id),
+		httpmock.NewStringResponder(404, ``))
+
+	err := accountApi.DeleteAccount(id, 0)
+	assert.True(t, IsNotFound(err), "Error is not a not found")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetryHonoursRetryAfterAndBudget(t *testing.T) {
+	policy := fastRetryPolicy()
+	policy.MaxElapsedTime = 500 * time.Millisecond
+	accountApi, _ := getRetryingAccountApi(policy)
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/health", hostname),
+		func(req *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(429, ``)
+			resp.Header.Set("Retry-After", "10")
+			return resp, nil
+		})
+
+	start := time.Now()
+	err := accountApi.IsHealthy()
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Less(t, time.Since(start), policy.MaxElapsedTime, "Retry-After beyond the budget was waited for")
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetryBackoffIsCancellable(t *testing.T) {
+	policy := fastRetryPolicy()
+	policy.InitialBackoff = 10 * time.Second
+	policy.MaxBackoff = 0
+	accountApi, _ := getRetryingAccountApi(policy)
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/v1/health", hostname),
+		httpmock.NewStringResponder(503, ``))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := accountApi.IsHealthyWithContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error is not context.DeadlineExceeded: %v", err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestRetryAfterParsing(t *testing.T) {
+	now := time.Date(2022, 10, 19, 9, 0, 0, 0, time.UTC)
+	header := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	wait, ok := retryAfter(header("3"), now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, wait)
+
+	wait, ok = retryAfter(header("Wed, 19 Oct 2022 09:00:05 GMT"), now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, wait)
+
+	_, ok = retryAfter(header("soon"), now)
+	assert.False(t, ok)
+
+	_, ok = retryAfter(&http.Response{Header: http.Header{}}, now)
+	assert.False(t, ok)
+}
+
+func TestRetryBackoffGrowsAndIsCapped(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		wait := policy.backoff(1)
+		assert.GreaterOrEqual(t, wait, 50*time.Millisecond)
+		assert.LessOrEqual(t, wait, 150*time.Millisecond)
+	}
+}
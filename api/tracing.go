@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"form3-interview-accounts/tracing"
+	"net/http"
+)
+
+// WithTracer starts a span for every AccountApi call and propagates it to the account API
+// in the traceparent header. Without it spans are not recorded, but trace context found in
+// the request context is still propagated.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(form3Api *AccountApi) error {
+		if tracer == nil {
+			return fmt.Errorf("tracer must not be nil")
+		}
+		form3Api.tracer = tracer
+		return nil
+	}
+}
+
+func (form3Api AccountApi) startSpan(ctx context.Context, operation Operation, req *http.Request, attributes map[string]any) (context.Context, tracing.Span) {
+	tracer := form3Api.tracer
+	if tracer == nil {
+		tracer = tracing.NoopTracer{}
+	}
+
+	ctx, span := tracer.Start(ctx, "AccountApi."+string(operation))
+	span.SetAttribute(tracing.AttributeHTTPMethod, req.Method)
+	span.SetAttribute(tracing.AttributeURLPath, req.URL.EscapedPath())
+	for key, value := range attributes {
+		span.SetAttribute(key, value)
+	}
+	return ctx, span
+}
+
+func endSpan(span tracing.Span, resp *http.Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	span.SetAttribute(tracing.AttributeHTTPStatusCode, resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.RecordError(fmt.Errorf("account api responded with status %s", resp.Status))
+	}
+}
+
+// accountAttributes returns the span attributes of the account ids that are set.
+func accountAttributes(id string, organisationID string) map[string]any {
+	attributes := map[string]any{}
+	if id != "" {
+		attributes[tracing.AttributeAccountID] = id
+	}
+	if organisationID != "" {
+		attributes[tracing.AttributeOrganisationID] = organisationID
+	}
+	return attributes
+}
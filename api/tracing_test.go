@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"form3-interview-accounts/tracing"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNilTracer(t *testing.T) {
+	accountApi, err := NewAccountApi(hostname, WithTracer(nil))
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Empty(t, accountApi, "Account API is not empty")
+}
+
+func TestSpanPerOperation(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	var traceParents []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		traceParents = append(traceParents, req.Header.Get("traceparent"))
+		return stringResponse(404, `{"error_message": "record does not exist"}`), nil
+	})
+	accountApi, err := NewAccountApi(hostname, WithTransport(transport), WithTracer(recorder))
+	assert.Empty(t, err, "Error is not empty")
+	id := "0d209d7f-d07a-4542-947f-5885fddddae7"
+
+	_, err = accountApi.GetAccount(id)
+	assert.True(t, IsNotFound(err))
+
+	spans := recorder.Spans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "AccountApi.get", spans[0].Name)
+	assert.True(t, spans[0].Ended, "Span is not ended")
+	assert.Equal(t, id, spans[0].Attributes[tracing.AttributeAccountID])
+	assert.Equal(t, "GET", spans[0].Attributes[tracing.AttributeHTTPMethod])
+	assert.Equal(t, "/v1/organisation/accounts/"+id, spans[0].Attributes[tracing.AttributeURLPath])
+	assert.Equal(t, 404, spans[0].Attributes[tracing.AttributeHTTPStatusCode])
+	assert.Equal(t, 1, len(spans[0].Errors))
+	assert.Equal(t, []string{spans[0].SpanContext.TraceParent()}, traceParents)
+}
+
+func TestCreateSpanHasOrganisation(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport), WithTracer(recorder))
+	account := getAccountData()
+
+	_, err := accountApi.CreateAccount(account)
+	assert.NotEmpty(t, err, "Error is empty")
+
+	spans := recorder.Spans()
+	assert.Equal(t, account.Data.ID, spans[0].Attributes[tracing.AttributeAccountID])
+	assert.Equal(t, account.Data.OrganisationID, spans[0].Attributes[tracing.AttributeOrganisationID])
+	assert.NotContains(t, spans[0].Attributes, tracing.AttributeHTTPStatusCode)
+	assert.Equal(t, 1, len(spans[0].Errors))
+}
+
+func TestTraceParentPropagatedWithoutTracer(t *testing.T) {
+	var traceParent string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		traceParent = req.Header.Get("traceparent")
+		return stringResponse(200, `{"status": "up"}`), nil
+	})
+	accountApi, _ := NewAccountApi(hostname, WithTransport(transport))
+	incoming := http.Header{}
+	incoming.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	err := accountApi.IsHealthyWithContext(tracing.Extract(context.Background(), incoming))
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceParent)
+
+	err = accountApi.IsHealthy()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Empty(t, traceParent, "Traceparent sent without trace context")
+}
@@ -0,0 +1,197 @@
+// Package builder assembles accounts fluently and validates them before they are used.
+package builder
+
+import (
+	"form3-interview-accounts/internal/clone"
+	"form3-interview-accounts/internal/validation"
+	"form3-interview-accounts/model"
+
+	"github.com/google/uuid"
+)
+
+// AccountBuilder assembles a model.AccountData without handling the nested pointers by hand.
+// It starts with the accounts type and a generated ID.
+//
+//	accountData, err := builder.NewAccountBuilder().
+//		WithOrganisationID(organisationID).
+//		WithCountry("GB").
+//		WithBankID("400300").
+//		WithBankIDCode(model.BankIDCodeGBDSC).
+//		WithBic("NWBKGB22").
+//		WithName("Samantha Holder").
+//		Build()
+type AccountBuilder struct {
+	account    model.Account
+	attributes model.AccountAttributes
+}
+
+func NewAccountBuilder() *AccountBuilder {
+	return &AccountBuilder{
+		account: model.Account{
+			ID:   uuid.New().String(),
+			Type: model.AccountResourceType,
+		},
+		attributes: model.AccountAttributes{
+			Name: []string{},
+		},
+	}
+}
+
+// Build validates the account and returns it, or the model.ValidationErrors listing its problems.
+// The account is a deep copy, so changing it does not change accounts built later.
+func (builder *AccountBuilder) Build() (model.AccountData, error) {
+	account := clone.Account(builder.account)
+	account.Attributes = clone.Attributes(&builder.attributes)
+	accountData := model.AccountData{Data: *account}
+
+	if err := validation.ValidateAccount(accountData); err != nil {
+		return model.AccountData{}, err
+	}
+
+	return accountData, nil
+}
+
+func (builder *AccountBuilder) WithID(id string) *AccountBuilder {
+	builder.account.ID = id
+	return builder
+}
+
+func (builder *AccountBuilder) WithOrganisationID(organisationID string) *AccountBuilder {
+	builder.account.OrganisationID = organisationID
+	return builder
+}
+
+func (builder *AccountBuilder) WithVersion(version int64) *AccountBuilder {
+	builder.account.Version = &version
+	return builder
+}
+
+func (builder *AccountBuilder) WithAcceptanceQualifier(acceptanceQualifier string) *AccountBuilder {
+	builder.attributes.AcceptanceQualifier = acceptanceQualifier
+	return builder
+}
+
+func (builder *AccountBuilder) WithAccountClassification(accountClassification model.AccountClassification) *AccountBuilder {
+	builder.attributes.AccountClassification = &accountClassification
+	return builder
+}
+
+func (builder *AccountBuilder) WithAccountMatchingOptOut(accountMatchingOptOut bool) *AccountBuilder {
+	builder.attributes.AccountMatchingOptOut = &accountMatchingOptOut
+	return builder
+}
+
+func (builder *AccountBuilder) WithAccountNumber(accountNumber string) *AccountBuilder {
+	builder.attributes.AccountNumber = accountNumber
+	return builder
+}
+
+func (builder *AccountBuilder) WithAlternativeNames(alternativeNames ...string) *AccountBuilder {
+	builder.attributes.AlternativeNames = append([]string(nil), alternativeNames...)
+	return builder
+}
+
+func (builder *AccountBuilder) WithBankID(bankID string) *AccountBuilder {
+	builder.attributes.BankID = bankID
+	return builder
+}
+
+func (builder *AccountBuilder) WithBankIDCode(bankIDCode model.BankIDCode) *AccountBuilder {
+	builder.attributes.BankIDCode = bankIDCode
+	return builder
+}
+
+func (builder *AccountBuilder) WithBaseCurrency(baseCurrency string) *AccountBuilder {
+	builder.attributes.BaseCurrency = baseCurrency
+	return builder
+}
+
+func (builder *AccountBuilder) WithBic(bic string) *AccountBuilder {
+	builder.attributes.Bic = bic
+	return builder
+}
+
+func (builder *AccountBuilder) WithCountry(country string) *AccountBuilder {
+	builder.attributes.Country = &country
+	return builder
+}
+
+func (builder *AccountBuilder) WithCustomerID(customerID string) *AccountBuilder {
+	builder.attributes.CustomerID = customerID
+	return builder
+}
+
+func (builder *AccountBuilder) WithIban(iban string) *AccountBuilder {
+	builder.attributes.Iban = iban
+	return builder
+}
+
+func (builder *AccountBuilder) WithJointAccount(jointAccount bool) *AccountBuilder {
+	builder.attributes.JointAccount = &jointAccount
+	return builder
+}
+
+func (builder *AccountBuilder) WithName(name ...string) *AccountBuilder {
+	builder.attributes.Name = append([]string{}, name...)
+	return builder
+}
+
+func (builder *AccountBuilder) WithNameMatchingStatus(nameMatchingStatus string) *AccountBuilder {
+	builder.attributes.NameMatchingStatus = nameMatchingStatus
+	return builder
+}
+
+func (builder *AccountBuilder) WithOrganisationIdentification(organisationIdentification model.OrganisationIdentification) *AccountBuilder {
+	builder.attributes.OrganisationIdentification = &organisationIdentification
+	return builder
+}
+
+func (builder *AccountBuilder) WithPrivateIdentification(privateIdentification model.PrivateIdentification) *AccountBuilder {
+	builder.attributes.PrivateIdentification = &privateIdentification
+	return builder
+}
+
+func (builder *AccountBuilder) WithProcessingService(processingService string) *AccountBuilder {
+	builder.attributes.ProcessingService = processingService
+	return builder
+}
+
+func (builder *AccountBuilder) WithReferenceMask(referenceMask string) *AccountBuilder {
+	builder.attributes.ReferenceMask = referenceMask
+	return builder
+}
+
+func (builder *AccountBuilder) WithSecondaryIdentification(secondaryIdentification string) *AccountBuilder {
+	builder.attributes.SecondaryIdentification = secondaryIdentification
+	return builder
+}
+
+func (builder *AccountBuilder) WithStatus(status model.AccountStatus) *AccountBuilder {
+	builder.attributes.Status = &status
+	return builder
+}
+
+func (builder *AccountBuilder) WithStatusReason(statusReason string) *AccountBuilder {
+	builder.attributes.StatusReason = statusReason
+	return builder
+}
+
+func (builder *AccountBuilder) WithSwitched(switched bool) *AccountBuilder {
+	builder.attributes.Switched = &switched
+	return builder
+}
+
+func (builder *AccountBuilder) WithUserDefinedData(userDefinedData ...model.UserDefinedData) *AccountBuilder {
+	builder.attributes.UserDefinedData = append([]model.UserDefinedData(nil), userDefinedData...)
+	return builder
+}
+
+func (builder *AccountBuilder) WithUserDefinedInformation(userDefinedInformation string) *AccountBuilder {
+	builder.attributes.UserDefinedInformation = userDefinedInformation
+	return builder
+}
+
+func (builder *AccountBuilder) WithValidationType(validationType string) *AccountBuilder {
+	builder.attributes.ValidationType = validationType
+	return builder
+}
@@ -0,0 +1,145 @@
+package builder_test
+
+import (
+	"encoding/json"
+	"errors"
+	"form3-interview-accounts/builder"
+	"form3-interview-accounts/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const organisationID = "ba61483c-d5c5-4f50-ae81-6b8c039bea43"
+
+func gbAccountBuilder() *builder.AccountBuilder {
+	return builder.NewAccountBuilder().
+		WithOrganisationID(organisationID).
+		WithCountry("GB").
+		WithBankID("400300").
+		WithBankIDCode(model.BankIDCodeGBDSC).
+		WithBic("NWBKGB22").
+		WithName("Samantha Holder")
+}
+
+func TestBuilderDefaults(t *testing.T) {
+	accountData, err := gbAccountBuilder().Build()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, "accounts", accountData.Data.Type)
+	_, err = uuid.Parse(accountData.Data.ID)
+	assert.Empty(t, err, "Generated id is not a UUID")
+	assert.Nil(t, accountData.Data.Version)
+
+	other, _ := gbAccountBuilder().Build()
+	assert.NotEqual(t, accountData.Data.ID, other.Data.ID, "Generated ids are not unique")
+}
+
+func TestBuilderSetsEveryAttribute(t *testing.T) {
+	accountData, err := gbAccountBuilder().
+		WithID("0d209d7f-d07a-4542-947f-5885fddddae7").
+		WithVersion(0).
+		WithAcceptanceQualifier("same_day").
+		WithAccountClassification(model.AccountClassificationPersonal).
+		WithAccountMatchingOptOut(false).
+		WithAccountNumber("41426819").
+		WithAlternativeNames("Sam Holder").
+		WithBaseCurrency("GBP").
+		WithCustomerID("customer-1").
+		WithIban("GB16NWBK40030041426819").
+		WithJointAccount(false).
+		WithNameMatchingStatus("supported").
+		WithOrganisationIdentification(model.OrganisationIdentification{Identification: "123654"}).
+		WithPrivateIdentification(model.PrivateIdentification{Identification: "13YH458762"}).
+		WithProcessingService("ABC Bank").
+		WithReferenceMask("############").
+		WithSecondaryIdentification("A1B2C3D4").
+		WithStatus(model.AccountStatusConfirmed).
+		WithStatusReason("unspecified").
+		WithSwitched(false).
+		WithUserDefinedData(model.UserDefinedData{Key: "key", Value: "value"}).
+		WithUserDefinedInformation("Some free text").
+		WithValidationType("card").
+		Build()
+	assert.Empty(t, err, "Error is not empty")
+
+	marshaledData, err := json.Marshal(accountData)
+	assert.Empty(t, err, "Error is not empty")
+	assert.JSONEq(t, `{"data": {
+		"attributes": {
+			"acceptance_qualifier": "same_day",
+			"account_classification": "Personal",
+			"account_matching_opt_out": false,
+			"account_number": "41426819",
+			"alternative_names": ["Sam Holder"],
+			"bank_id": "400300",
+			"bank_id_code": "GBDSC",
+			"base_currency": "GBP",
+			"bic": "NWBKGB22",
+			"country": "GB",
+			"customer_id": "customer-1",
+			"iban": "GB16NWBK40030041426819",
+			"joint_account": false,
+			"name": ["Samantha Holder"],
+			"name_matching_status": "supported",
+			"organisation_identification": {"identification": "123654"},
+			"private_identification": {"identification": "13YH458762"},
+			"processing_service": "ABC Bank",
+			"reference_mask": "############",
+			"secondary_identification": "A1B2C3D4",
+			"status": "confirmed",
+			"status_reason": "unspecified",
+			"switched": false,
+			"user_defined_data": [{"key": "key", "value": "value"}],
+			"user_defined_information": "Some free text",
+			"validation_type": "card"
+		},
+		"id": "0d209d7f-d07a-4542-947f-5885fddddae7",
+		"organisation_id": "`+organisationID+`",
+		"type": "accounts",
+		"version": 0
+	}}`, string(marshaledData))
+}
+
+func TestBuilderReturnsValidationErrors(t *testing.T) {
+	accountData, err := builder.NewAccountBuilder().
+		WithCountry("GB").
+		WithBic("DEUTDEFF").
+		Build()
+
+	var validationErrors model.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, []string{
+		"/data/organisation_id",
+		"/data/attributes/bank_id",
+		"/data/attributes/bank_id_code",
+		"/data/attributes/bic",
+	}, validationErrors.Pointers())
+	assert.Empty(t, accountData, "Account data is not empty")
+}
+
+func TestBuiltAccountsDoNotShareState(t *testing.T) {
+	builder := gbAccountBuilder()
+	first, _ := builder.Build()
+	second, err := builder.WithName("Sam Holder").WithCountry("IE").WithBankID("931152").WithBic("AIBKIE2D").Build()
+	assert.Empty(t, err, "Error is not empty")
+
+	assert.Equal(t, []string{"Samantha Holder"}, first.Data.Attributes.Name)
+	assert.Equal(t, "GB", *first.Data.Attributes.Country)
+	assert.Equal(t, []string{"Sam Holder"}, second.Data.Attributes.Name)
+	assert.Equal(t, "IE", *second.Data.Attributes.Country)
+}
+
+func TestChangingBuiltAccountDoesNotChangeBuilder(t *testing.T) {
+	builder := gbAccountBuilder().WithAlternativeNames("Sam Holder")
+	first, _ := builder.Build()
+	first.Data.Attributes.Name[0] = "Changed"
+	first.Data.Attributes.AlternativeNames[0] = "Changed"
+	*first.Data.Attributes.Country = "FR"
+
+	second, err := builder.Build()
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, []string{"Samantha Holder"}, second.Data.Attributes.Name)
+	assert.Equal(t, []string{"Sam Holder"}, second.Data.Attributes.AlternativeNames)
+	assert.Equal(t, "GB", *second.Data.Attributes.Country)
+}
@@ -0,0 +1,18 @@
+// Command mockaccountapi serves an in-memory emulation of the account API.
+package main
+
+import (
+	"flag"
+	"form3-interview-accounts/fake"
+	"form3-interview-accounts/mockserver"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+
+	log.Printf("mock account api listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mockserver.NewHandler(fake.NewAccountOperations())))
+}
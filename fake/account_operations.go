@@ -0,0 +1,411 @@
+// Package fake provides an in-memory implementation of service.AccountOperations for unit
+// tests that should not depend on a running account API.
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"form3-interview-accounts/api"
+	"form3-interview-accounts/internal/clone"
+	"form3-interview-accounts/internal/util"
+	"form3-interview-accounts/internal/validation"
+	"form3-interview-accounts/model"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const accountsPath = "/v1/organisation/accounts"
+const defaultPageSize = 100
+
+// AccountOperations keeps accounts in memory and answers like the account API: accounts
+// start at version 0, updates increment it, duplicates and stale versions fail with a 409
+// and missing accounts with a 404, all as *api.APIError.
+type AccountOperations struct {
+	mutex    sync.Mutex
+	accounts map[string]model.Account
+	failures map[api.Operation]error
+	calls    map[api.Operation]int
+	latency  time.Duration
+	now      func() time.Time
+}
+
+func NewAccountOperations() *AccountOperations {
+	return &AccountOperations{
+		accounts: make(map[string]model.Account),
+		failures: make(map[api.Operation]error),
+		calls:    make(map[api.Operation]int),
+		now:      time.Now,
+	}
+}
+
+// SetFailure makes every call of operation fail with err until ClearFailures is called.
+func (fake *AccountOperations) SetFailure(operation api.Operation, err error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.failures[operation] = err
+}
+
+func (fake *AccountOperations) ClearFailures() {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.failures = make(map[api.Operation]error)
+}
+
+// SetLatency delays every call by latency. A call whose context is done while waiting
+// fails with the context error.
+func (fake *AccountOperations) SetLatency(latency time.Duration) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.latency = latency
+}
+
+// SetClock replaces the clock used for created_on and modified_on.
+func (fake *AccountOperations) SetClock(now func() time.Time) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.now = now
+}
+
+// Calls returns how many times operation was called, including failed calls.
+func (fake *AccountOperations) Calls(operation api.Operation) int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.calls[operation]
+}
+
+func (fake *AccountOperations) IsHealthy() error {
+	return fake.IsHealthyWithContext(context.Background())
+}
+
+func (fake *AccountOperations) IsHealthyWithContext(ctx context.Context) error {
+	return fake.call(ctx, api.OperationHealth)
+}
+
+func (fake *AccountOperations) GetAccounts(filter model.AccountFilter) ([]model.Account, error) {
+	return fake.GetAccountsWithContext(context.Background(), filter)
+}
+
+func (fake *AccountOperations) GetAccountsWithContext(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+	data, err := fake.GetAccountsPageWithContext(ctx, filter, model.PageRequest{})
+	if err != nil {
+		return []model.Account{}, err
+	}
+	return data.Data, nil
+}
+
+func (fake *AccountOperations) GetAccountsPage(filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error) {
+	return fake.GetAccountsPageWithContext(context.Background(), filter, page)
+}
+
+func (fake *AccountOperations) GetAccountsPageWithContext(ctx context.Context, filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error) {
+	if err := fake.call(ctx, api.OperationList); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateAccountFilter(filter); err != nil {
+		return nil, err
+	}
+	if page.Number < 0 || page.Size < 0 {
+		return nil, NewAPIError(http.StatusBadRequest, "page number and size must not be negative")
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	var matching []model.Account
+	for _, account := range fake.accounts {
+		if matches(account, filter) {
+			matching = append(matching, account)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		if !matching[i].CreatedOn.Equal(*matching[j].CreatedOn) {
+			return matching[i].CreatedOn.Before(*matching[j].CreatedOn)
+		}
+		return matching[i].ID < matching[j].ID
+	})
+
+	return listPage(matching, filter, page), nil
+}
+
+func (fake *AccountOperations) GetAccount(id string) (*model.Account, error) {
+	return fake.GetAccountWithContext(context.Background(), id)
+}
+
+func (fake *AccountOperations) GetAccountWithContext(ctx context.Context, id string) (*model.Account, error) {
+	if err := fake.call(ctx, api.OperationGet); err != nil {
+		return nil, err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	account, ok := fake.accounts[id]
+	if !ok {
+		return nil, notFound(id)
+	}
+	return clone.Account(account), nil
+}
+
+func (fake *AccountOperations) CreateAccount(accountBody model.AccountData) (*model.Account, error) {
+	return fake.CreateAccountWithContext(context.Background(), accountBody)
+}
+
+func (fake *AccountOperations) CreateAccountWithContext(ctx context.Context, accountBody model.AccountData) (*model.Account, error) {
+	if err := fake.call(ctx, api.OperationCreate); err != nil {
+		return nil, err
+	}
+	if err := validateNewAccount(accountBody.Data); err != nil {
+		return nil, err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	id := accountBody.Data.ID
+	if _, ok := fake.accounts[id]; ok {
+		return nil, NewAPIError(http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+	}
+
+	account := *clone.Account(accountBody.Data)
+	now := fake.now().UTC()
+	var version int64 = 0
+	account.CreatedOn = &now
+	account.ModifiedOn = &now
+	account.Version = &version
+	fake.accounts[id] = account
+
+	return clone.Account(account), nil
+}
+
+func (fake *AccountOperations) PatchAccount(id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error) {
+	return fake.PatchAccountWithContext(context.Background(), id, version, attributes)
+}
+
+func (fake *AccountOperations) PatchAccountWithContext(ctx context.Context, id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error) {
+	if err := fake.call(ctx, api.OperationPatch); err != nil {
+		return nil, err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	account, ok := fake.accounts[id]
+	if !ok {
+		return nil, notFound(id)
+	}
+	if *account.Version != int64(version) {
+		return nil, invalidVersion()
+	}
+
+	if err := validateEnums(attributes.AccountClassification, attributes.Status, attributes.BankIDCode); err != nil {
+		return nil, err
+	}
+	patched := applyPatch(account, attributes)
+	now := fake.now().UTC()
+	nextVersion := *account.Version + 1
+	patched.ModifiedOn = &now
+	patched.Version = &nextVersion
+	fake.accounts[id] = patched
+
+	return clone.Account(patched), nil
+}
+
+func (fake *AccountOperations) DeleteAccount(id string, version int) error {
+	return fake.DeleteAccountWithContext(context.Background(), id, version)
+}
+
+func (fake *AccountOperations) DeleteAccountWithContext(ctx context.Context, id string, version int) error {
+	if err := fake.call(ctx, api.OperationDelete); err != nil {
+		return err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	account, ok := fake.accounts[id]
+	if !ok {
+		return notFound(id)
+	}
+	if *account.Version != int64(version) {
+		return invalidVersion()
+	}
+
+	delete(fake.accounts, id)
+	return nil
+}
+
+// call records the call, waits for the latency and returns the injected failure, if any.
+func (fake *AccountOperations) call(ctx context.Context, operation api.Operation) error {
+	fake.mutex.Lock()
+	fake.calls[operation]++
+	latency := fake.latency
+	failure := fake.failures[operation]
+	fake.mutex.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	} else if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return failure
+}
+
+// NewAPIError builds the error the account API answers with for status and message.
+func NewAPIError(status int, message string) *api.APIError {
+	body, _ := json.Marshal(map[string]string{"error_message": message})
+	return &api.APIError{
+		StatusCode:   status,
+		ErrorMessage: message,
+		Body:         body,
+	}
+}
+
+func notFound(id string) *api.APIError {
+	return NewAPIError(http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+}
+
+func invalidVersion() *api.APIError {
+	return NewAPIError(http.StatusConflict, "invalid version")
+}
+
+// validateNewAccount performs the checks of the account API, which are looser than the
+// ones of the validation package.
+func validateNewAccount(account model.Account) error {
+	var failures []string
+	if _, err := uuid.Parse(account.ID); err != nil {
+		failures = append(failures, "id in body must be of type uuid")
+	}
+	if _, err := uuid.Parse(account.OrganisationID); err != nil {
+		failures = append(failures, "organisation_id in body must be of type uuid")
+	}
+	if account.Type != model.AccountResourceType {
+		failures = append(failures, "type in body should be one of [accounts]")
+	}
+	if account.Attributes == nil || account.Attributes.Country == nil {
+		failures = append(failures, "country in body is required")
+	}
+	if account.Attributes != nil {
+		failures = append(failures, enumFailures(account.Attributes.AccountClassification, account.Attributes.Status, account.Attributes.BankIDCode)...)
+	}
+
+	return validationFailure(failures)
+}
+
+func validateEnums(classification *model.AccountClassification, status *model.AccountStatus, bankIDCode model.BankIDCode) error {
+	return validationFailure(enumFailures(classification, status, bankIDCode))
+}
+
+// enumFailures lists the enum values the API does not know.
+func enumFailures(classification *model.AccountClassification, status *model.AccountStatus, bankIDCode model.BankIDCode) []string {
+	var failures []string
+	if classification != nil && !classification.IsValid() {
+		failures = append(failures, "account_classification in body should be one of [Personal Business]")
+	}
+	if status != nil && !status.IsValid() {
+		failures = append(failures, "status in body should be one of [pending confirmed failed]")
+	}
+	if !bankIDCode.IsValid() {
+		failures = append(failures, fmt.Sprintf("bank_id_code %s in body is not supported", bankIDCode))
+	}
+	return failures
+}
+
+func validationFailure(failures []string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	message := "validation failure list:"
+	for _, failure := range failures {
+		message += "\n" + failure
+	}
+	apiError := NewAPIError(http.StatusBadRequest, message)
+	apiError.ErrorCode = "validation_failure"
+	return apiError
+}
+
+func matches(account model.Account, filter model.AccountFilter) bool {
+	attributes := account.Attributes
+	if attributes == nil {
+		attributes = &model.AccountAttributes{}
+	}
+
+	status := model.AccountStatus("")
+	if attributes.Status != nil {
+		status = *attributes.Status
+	}
+	country := ""
+	if attributes.Country != nil {
+		country = *attributes.Country
+	}
+
+	return matchesValue(filter.BankID, attributes.BankID) &&
+		matchesValue(string(filter.BankIDCode), string(attributes.BankIDCode)) &&
+		matchesValue(filter.AccountNumber, attributes.AccountNumber) &&
+		matchesValue(filter.Iban, attributes.Iban) &&
+		matchesValue(filter.Country, country) &&
+		matchesValue(filter.CustomerID, attributes.CustomerID) &&
+		matchesValue(string(filter.Status), string(status))
+}
+
+func matchesValue(filterValue string, value string) bool {
+	return filterValue == "" || filterValue == value
+}
+
+// listPage slices a page out of accounts, along with links shaped like the API ones.
+func listPage(accounts []model.Account, filter model.AccountFilter, page model.PageRequest) *model.AccountsData {
+	size := page.Size
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	lastPage := 0
+	if len(accounts) > 0 {
+		lastPage = (len(accounts) - 1) / size
+	}
+
+	data := &model.AccountsData{Data: []model.Account{}}
+	if page.Number <= lastPage {
+		start := page.Number * size
+		end := len(accounts)
+		if end-start > size {
+			end = start + size
+		}
+		for _, account := range accounts[start:end] {
+			data.Data = append(data.Data, *clone.Account(account))
+		}
+	}
+
+	link := func(number string) string {
+		queryParameters := filter.QueryParameters()
+		queryParameters["page[number]"] = number
+		queryParameters["page[size]"] = strconv.Itoa(size)
+		return util.BuildUrl("", accountsPath, queryParameters)
+	}
+
+	data.Links = &model.Links{
+		First: link("first"),
+		Last:  link("last"),
+		Self:  link(strconv.Itoa(page.Number)),
+	}
+	if page.Number < lastPage {
+		data.Links.Next = link(strconv.Itoa(page.Number + 1))
+	}
+	if page.Number > 0 {
+		data.Links.Prev = link(strconv.Itoa(page.Number - 1))
+	}
+
+	return data
+}
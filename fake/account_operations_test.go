@@ -0,0 +1,222 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"form3-interview-accounts/api"
+	"form3-interview-accounts/fake"
+	"form3-interview-accounts/model"
+	"form3-interview-accounts/service"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ service.AccountOperations = fake.NewAccountOperations()
+
+func TestCreateAndGetAccount(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+
+	created, err := fakeOperations.CreateAccount(accountData)
+	assert.Nil(t, err, "Error is not empty")
+	assert.Equal(t, int64(0), *created.Version)
+	assert.NotNil(t, created.CreatedOn)
+
+	account, err := fakeOperations.GetAccount(accountData.Data.ID)
+	assert.Nil(t, err, "Error is not empty")
+	assert.Equal(t, created, account)
+
+	account.Attributes.Name[0] = "Changed"
+	stored, _ := fakeOperations.GetAccount(accountData.Data.ID)
+	assert.Equal(t, "Samantha Holder", stored.Attributes.Name[0], "Stored account was modified")
+}
+
+func TestCreateDuplicateAccount(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	_, _ = fakeOperations.CreateAccount(accountData)
+
+	_, err := fakeOperations.CreateAccount(accountData)
+	assert.True(t, api.IsConflict(err), "Error is not a conflict")
+}
+
+func TestCreateInvalidAccount(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	accountData.Data.ID = ""
+
+	_, err := fakeOperations.CreateAccount(accountData)
+	assert.True(t, api.IsValidationError(err), "Error is not a validation error")
+}
+
+func TestCreateAccountWithUnknownEnumValue(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	status := model.AccountStatus("closed")
+	accountData.Data.Attributes.Status = &status
+
+	_, err := fakeOperations.CreateAccount(accountData)
+	assert.True(t, api.IsValidationError(err), "Error is not a validation error")
+
+	_, err = fakeOperations.GetAccount(accountData.Data.ID)
+	assert.True(t, api.IsNotFound(err), "Invalid account was stored")
+}
+
+func TestStoredAccountIsDeepCopied(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	accountData.Data.Attributes.PrivateIdentification = &model.PrivateIdentification{Address: []string{"10 Avenue des Champs"}}
+	_, _ = fakeOperations.CreateAccount(accountData)
+
+	accountData.Data.Attributes.PrivateIdentification.Address[0] = "Changed"
+	*accountData.Data.Attributes.Country = "FR"
+	stored, _ := fakeOperations.GetAccount(accountData.Data.ID)
+	assert.Equal(t, "10 Avenue des Champs", stored.Attributes.PrivateIdentification.Address[0])
+	assert.Equal(t, "GB", *stored.Attributes.Country)
+}
+
+func TestGetMissingAccount(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+
+	_, err := fakeOperations.GetAccount(uuid.NewString())
+	assert.True(t, api.IsNotFound(err), "Error is not a not found")
+}
+
+func TestDeleteAccount(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	_, _ = fakeOperations.CreateAccount(accountData)
+
+	err := fakeOperations.DeleteAccount(accountData.Data.ID, 1)
+	assert.True(t, api.IsConflict(err), "Error is not a conflict")
+
+	err = fakeOperations.DeleteAccount(accountData.Data.ID, 0)
+	assert.Nil(t, err, "Error is not empty")
+
+	err = fakeOperations.DeleteAccount(accountData.Data.ID, 0)
+	assert.True(t, api.IsNotFound(err), "Error is not a not found")
+}
+
+func TestPatchAccount(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	_, _ = fakeOperations.CreateAccount(accountData)
+
+	patched, err := fakeOperations.PatchAccount(accountData.Data.ID, 0, model.AccountAttributesPatch{CustomerID: "customer-1"})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Equal(t, int64(1), *patched.Version)
+	assert.Equal(t, "customer-1", patched.Attributes.CustomerID)
+	assert.Equal(t, []string{"Samantha Holder"}, patched.Attributes.Name)
+
+	_, err = fakeOperations.PatchAccount(accountData.Data.ID, 0, model.AccountAttributesPatch{CustomerID: "customer-2"})
+	assert.True(t, api.IsConflict(err), "Error is not a conflict")
+}
+
+func TestPatchAccountWithUnknownEnumValue(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	accountData := fake.NewAccountData("GB")
+	_, _ = fakeOperations.CreateAccount(accountData)
+	status := model.AccountStatus("closed")
+
+	_, err := fakeOperations.PatchAccount(accountData.Data.ID, 0, model.AccountAttributesPatch{Status: &status})
+	assert.True(t, api.IsValidationError(err), "Error is not a validation error")
+
+	stored, _ := fakeOperations.GetAccount(accountData.Data.ID)
+	assert.Equal(t, int64(0), *stored.Version)
+	assert.Nil(t, stored.Attributes.Status)
+}
+
+func TestGetAccountsWithFilter(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	_, _ = fakeOperations.CreateAccount(fake.NewAccountData("GB"))
+	_, _ = fakeOperations.CreateAccount(fake.NewAccountData("FR"))
+
+	accounts, err := fakeOperations.GetAccounts(model.AccountFilter{Country: "FR"})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, "FR", *accounts[0].Attributes.Country)
+
+	_, err = fakeOperations.GetAccounts(model.AccountFilter{Country: "XX"})
+	assert.NotNil(t, err, "Error is empty")
+}
+
+func TestListAccountsAcrossPages(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	now := time.Now()
+	fakeOperations.SetClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	var ids []string
+	for i := 0; i < 5; i++ {
+		accountData := fake.NewAccountData("GB")
+		ids = append(ids, accountData.Data.ID)
+		_, _ = fakeOperations.CreateAccount(accountData)
+	}
+
+	page, err := fakeOperations.GetAccountsPage(model.AccountFilter{}, model.PageRequest{Number: 2, Size: 2})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Len(t, page.Data, 1)
+	assert.Empty(t, page.Links.Next)
+	assert.NotEmpty(t, page.Links.Prev)
+
+	accountService, _ := service.NewAccountService(fakeOperations)
+	iterator := accountService.ListAccounts(context.Background(), model.AccountFilter{}, 2)
+	var listed []string
+	for iterator.Next() {
+		listed = append(listed, iterator.Account().ID)
+	}
+	assert.Nil(t, iterator.Err(), "Error is not empty")
+	assert.Equal(t, ids, listed)
+}
+
+func TestListAccountsWithInvalidPage(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+
+	for _, page := range []model.PageRequest{{Number: -1}, {Size: -1}} {
+		_, err := fakeOperations.GetAccountsPage(model.AccountFilter{}, page)
+		assert.True(t, api.IsValidationError(err), "Error is not a validation error")
+	}
+}
+
+func TestListAccountsBeyondLastPage(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	_, _ = fakeOperations.CreateAccount(fake.NewAccountData("GB"))
+
+	for _, page := range []model.PageRequest{{Number: 1}, {Number: math.MaxInt / 50, Size: 100}, {Number: 1, Size: math.MaxInt}} {
+		data, err := fakeOperations.GetAccountsPage(model.AccountFilter{}, page)
+		assert.Nil(t, err, "Error is not empty")
+		assert.Empty(t, data.Data)
+		assert.Empty(t, data.Links.Next)
+	}
+	data, err := fakeOperations.GetAccountsPage(model.AccountFilter{}, model.PageRequest{Size: math.MaxInt})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Len(t, data.Data, 1)
+}
+
+func TestFailureInjection(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	failure := fake.NewAPIError(503, "service unavailable")
+	fakeOperations.SetFailure(api.OperationHealth, failure)
+
+	err := fakeOperations.IsHealthy()
+	assert.True(t, errors.Is(err, failure), "Error is not the injected failure")
+	assert.Equal(t, 1, fakeOperations.Calls(api.OperationHealth))
+
+	fakeOperations.ClearFailures()
+	assert.Nil(t, fakeOperations.IsHealthy(), "Error is not empty")
+}
+
+func TestLatencyRespectsContext(t *testing.T) {
+	fakeOperations := fake.NewAccountOperations()
+	fakeOperations.SetLatency(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := fakeOperations.GetAccountWithContext(ctx, uuid.NewString())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error is not a deadline exceeded")
+}
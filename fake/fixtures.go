@@ -0,0 +1,26 @@
+package fake
+
+import (
+	"form3-interview-accounts/model"
+
+	"github.com/google/uuid"
+)
+
+// OrganisationID is the organisation of the accounts made by NewAccountData.
+const OrganisationID = "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"
+
+// NewAccountData returns an account in country with a new ID, ready to be created
+// through the fake or the mock server.
+func NewAccountData(country string) model.AccountData {
+	return model.AccountData{
+		Data: model.Account{
+			ID:             uuid.NewString(),
+			OrganisationID: OrganisationID,
+			Type:           model.AccountResourceType,
+			Attributes: &model.AccountAttributes{
+				Country: &country,
+				Name:    []string{"Samantha Holder"},
+			},
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package fake
+
+import (
+	"form3-interview-accounts/internal/clone"
+	"form3-interview-accounts/model"
+)
+
+// applyPatch returns a copy of account with the attributes set in patch. Like the
+// JSON body of a patch, empty strings and slices and nil pointers leave an attribute as is.
+func applyPatch(account model.Account, patch model.AccountAttributesPatch) model.Account {
+	patched := *clone.Account(account)
+	if patched.Attributes == nil {
+		patched.Attributes = &model.AccountAttributes{}
+	}
+	attributes := patched.Attributes
+
+	patchString(&attributes.AcceptanceQualifier, patch.AcceptanceQualifier)
+	patchPointer(&attributes.AccountClassification, patch.AccountClassification)
+	patchPointer(&attributes.AccountMatchingOptOut, patch.AccountMatchingOptOut)
+	patchString(&attributes.AccountNumber, patch.AccountNumber)
+	patchSlice(&attributes.AlternativeNames, patch.AlternativeNames)
+	patchString(&attributes.BankID, patch.BankID)
+	patchString(&attributes.BankIDCode, patch.BankIDCode)
+	patchString(&attributes.BaseCurrency, patch.BaseCurrency)
+	patchString(&attributes.Bic, patch.Bic)
+	patchString(&attributes.CustomerID, patch.CustomerID)
+	patchString(&attributes.Iban, patch.Iban)
+	patchPointer(&attributes.JointAccount, patch.JointAccount)
+	patchSlice(&attributes.Name, patch.Name)
+	patchString(&attributes.NameMatchingStatus, patch.NameMatchingStatus)
+	if patch.OrganisationIdentification != nil {
+		attributes.OrganisationIdentification = clone.OrganisationIdentification(patch.OrganisationIdentification)
+	}
+	if patch.PrivateIdentification != nil {
+		attributes.PrivateIdentification = clone.PrivateIdentification(patch.PrivateIdentification)
+	}
+	patchString(&attributes.ProcessingService, patch.ProcessingService)
+	patchString(&attributes.ReferenceMask, patch.ReferenceMask)
+	patchString(&attributes.SecondaryIdentification, patch.SecondaryIdentification)
+	patchPointer(&attributes.Status, patch.Status)
+	patchString(&attributes.StatusReason, patch.StatusReason)
+	patchPointer(&attributes.Switched, patch.Switched)
+	patchSlice(&attributes.UserDefinedData, patch.UserDefinedData)
+	patchString(&attributes.UserDefinedInformation, patch.UserDefinedInformation)
+	patchString(&attributes.ValidationType, patch.ValidationType)
+
+	return patched
+}
+
+func patchString[T ~string](target *T, value T) {
+	if value != "" {
+		*target = value
+	}
+}
+
+func patchPointer[T any](target **T, value *T) {
+	if value != nil {
+		*target = clone.Pointer(value)
+	}
+}
+
+func patchSlice[T any](target *[]T, values []T) {
+	if len(values) > 0 {
+		*target = clone.Slice(values)
+	}
+}
@@ -0,0 +1,125 @@
+// Package iban validates International Bank Account Numbers against the structure
+// published in the SWIFT IBAN registry and their mod-97 checksum.
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidFormat   = errors.New("invalid iban format")
+	ErrUnknownCountry  = errors.New("unknown iban country")
+	ErrInvalidLength   = errors.New("invalid iban length")
+	ErrInvalidBban     = errors.New("invalid iban bban")
+	ErrInvalidChecksum = errors.New("invalid iban checksum")
+)
+
+var ibanFormat = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]+$`)
+
+type countryFormat struct {
+	length int
+	bban   *regexp.Regexp
+}
+
+// bbanStructures holds the BBAN structure of every country of the IBAN registry using
+// the registry notation: n for digits, a for upper case letters, c for both.
+var bbanStructures = map[string]string{
+	"AD": "4n4n12c", "AE": "3n16n", "AL": "8n16c", "AT": "5n11n", "AZ": "4a20c",
+	"BA": "3n3n8n2n", "BE": "3n7n2n", "BG": "4a4n2n8c", "BH": "4a14c", "BR": "8n5n10n1a1c",
+	"CH": "5n12c", "CR": "4n14n", "CY": "3n5n16c", "CZ": "4n6n10n", "DE": "8n10n",
+	"DK": "4n9n1n", "DO": "4c20n", "EE": "2n2n11n1n", "EG": "4n4n17n", "ES": "4n4n1n1n10n",
+	"FI": "3n11n", "FO": "4n9n1n", "FR": "5n5n11c2n", "GB": "4a6n8n", "GE": "2a16n",
+	"GI": "4a15c", "GL": "4n9n1n", "GR": "3n4n16c", "GT": "4c20c", "HR": "7n10n",
+	"HU": "3n4n1n15n1n", "IE": "4a6n8n", "IL": "3n3n13n", "IS": "4n2n6n10n", "IT": "1a5n5n12c",
+	"JO": "4a4n18c", "KW": "4a22c", "KZ": "3n13c", "LB": "4n20c", "LI": "5n12c",
+	"LT": "5n11n", "LU": "3n13c", "LV": "4a13c", "MC": "5n5n11c2n", "MD": "2c18c",
+	"ME": "3n13n2n", "MK": "3n10c2n", "MR": "5n5n11n2n", "MT": "4a5n18c", "MU": "4a2n2n12n3n3a",
+	"NL": "4a10n", "NO": "4n6n1n", "PK": "4a16c", "PL": "8n16n", "PS": "4a21c",
+	"PT": "4n4n11n2n", "QA": "4a21c", "RO": "4a16c", "RS": "3n13n2n", "SA": "2n18c",
+	"SE": "3n16n1n", "SI": "5n8n2n", "SK": "4n6n10n", "SM": "1a5n5n12c", "TN": "2n3n13n2n",
+	"TR": "5n1n16c", "UA": "6n19c", "VG": "4a16n", "XK": "4n10n2n",
+}
+
+var countryFormats = compileCountryFormats(bbanStructures)
+
+var bbanStructurePart = regexp.MustCompile(`([0-9]+)([nac])`)
+
+func compileCountryFormats(structures map[string]string) map[string]countryFormat {
+	characterClasses := map[string]string{"n": "[0-9]", "a": "[A-Z]", "c": "[A-Z0-9]"}
+
+	formats := make(map[string]countryFormat, len(structures))
+	for country, structure := range structures {
+		length := 4
+		pattern := "^"
+		for _, part := range bbanStructurePart.FindAllStringSubmatch(structure, -1) {
+			partLength, _ := strconv.Atoi(part[1])
+			length += partLength
+			pattern += fmt.Sprintf("%s{%d}", characterClasses[part[2]], partLength)
+		}
+		formats[country] = countryFormat{length: length, bban: regexp.MustCompile(pattern + "$")}
+	}
+	return formats
+}
+
+// Normalize turns an IBAN in print format, e.g. "gb82 west 1234 5698 7654 32", into
+// the electronic format expected by Validate and the account API.
+func Normalize(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// Validate checks that iban, in electronic format, has the length and BBAN structure
+// of its country and a valid checksum. The returned error wraps one of the Err values.
+func Validate(iban string) error {
+	if !ibanFormat.MatchString(iban) {
+		return fmt.Errorf("%w: %q must be a country code, two check digits and upper case letters or digits", ErrInvalidFormat, iban)
+	}
+
+	country := iban[:2]
+	format, ok := countryFormats[country]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCountry, country)
+	}
+
+	if len(iban) != format.length {
+		return fmt.Errorf("%w: %s ibans have %d characters, got %d", ErrInvalidLength, country, format.length, len(iban))
+	}
+
+	if !format.bban.MatchString(iban[4:]) {
+		return fmt.Errorf("%w: %s does not match the %s structure %s", ErrInvalidBban, iban[4:], country, bbanStructures[country])
+	}
+
+	if checksum(iban) != 1 {
+		return fmt.Errorf("%w: %s", ErrInvalidChecksum, iban)
+	}
+
+	return nil
+}
+
+// Country returns the country code of iban.
+func Country(iban string) string {
+	if len(iban) < 2 {
+		return ""
+	}
+	return iban[:2]
+}
+
+// checksum computes the ISO 7064 mod-97 remainder of iban with its first four characters
+// moved to the end and letters replaced by 10 to 35.
+func checksum(iban string) int {
+	rearranged := iban[4:] + iban[:4]
+
+	remainder := 0
+	for _, character := range rearranged {
+		if character >= 'A' && character <= 'Z' {
+			value := int(character-'A') + 10
+			remainder = (remainder*100 + value) % 97
+		} else {
+			remainder = (remainder*10 + int(character-'0')) % 97
+		}
+	}
+	return remainder
+}
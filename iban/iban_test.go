@@ -0,0 +1,71 @@
+package iban
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidIbans(t *testing.T) {
+	ibans := []string{
+		"GB82WEST12345698765432",
+		"GB16NWBK40030041426819",
+		"DE89370400440532013000",
+		"FR1420041010050500013M02606",
+		"NL91ABNA0417164300",
+		"BE68539007547034",
+		"CH9300762011623852957",
+		"ES9121000418450200051332",
+		"IT60X0542811101000000123456",
+		"PL61109010140000071219812874",
+		"PT50000201231234567890154",
+		"GR1601101250000000012300695",
+		"LU280019400644750000",
+		"SE4550000000058398257466",
+		"DK5000400440116243",
+		"FI2112345600000785",
+		"IE29AIBK93115212345678",
+		"EE382200221020145685",
+		"NO9386011117947",
+		"MT84MALT011000012345MTLCAST001S",
+	}
+
+	for _, iban := range ibans {
+		assert.Empty(t, Validate(iban), "Error is not empty for %s", iban)
+	}
+}
+
+func TestInvalidIbans(t *testing.T) {
+	ibans := map[string]error{
+		"":                            ErrInvalidFormat,
+		"GB82 WEST 1234 5698 7654 32": ErrInvalidFormat,
+		"gb82west12345698765432":      ErrInvalidFormat,
+		"GBXXWEST12345698765432":      ErrInvalidFormat,
+		"US64SVBKUS6S3300958879":      ErrUnknownCountry,
+		"GB82WEST1234569876543":       ErrInvalidLength,
+		"DE8937040044053201300099":    ErrInvalidLength,
+		"GB821234WEST98765432AB":      ErrInvalidBban,
+		"NL91ABNA041716430A":          ErrInvalidBban,
+		"GB83WEST12345698765432":      ErrInvalidChecksum,
+		"DE89370400440532013001":      ErrInvalidChecksum,
+	}
+
+	for iban, expected := range ibans {
+		err := Validate(iban)
+		assert.True(t, errors.Is(err, expected), "Error for %q is %v, expected %v", iban, err, expected)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "GB82WEST12345698765432", Normalize(" gb82 west 1234 5698 7654 32 "))
+	assert.Empty(t, Validate(Normalize("gb82 west 1234 5698 7654 32")))
+}
+
+func TestCountryFormats(t *testing.T) {
+	assert.Equal(t, 22, countryFormats["GB"].length)
+	assert.Equal(t, 27, countryFormats["FR"].length)
+	assert.Equal(t, 30, countryFormats["MU"].length)
+	assert.Equal(t, "GB", Country("GB82WEST12345698765432"))
+	assert.Equal(t, "", Country("G"))
+}
@@ -0,0 +1,91 @@
+// Package clone deep copies accounts, so copies can be changed without affecting the original.
+package clone
+
+import "form3-interview-accounts/model"
+
+// Account returns a deep copy of account.
+func Account(account model.Account) *model.Account {
+	clone := account
+	clone.Attributes = Attributes(account.Attributes)
+	clone.CreatedOn = Pointer(account.CreatedOn)
+	clone.ModifiedOn = Pointer(account.ModifiedOn)
+	clone.Version = Pointer(account.Version)
+	if account.Relationships != nil {
+		clone.Relationships = &model.AccountRelationships{
+			AccountEvents: relationship(account.Relationships.AccountEvents),
+			MasterAccount: relationship(account.Relationships.MasterAccount),
+		}
+	}
+	return &clone
+}
+
+// Attributes returns a deep copy of attributes, or nil.
+func Attributes(attributes *model.AccountAttributes) *model.AccountAttributes {
+	if attributes == nil {
+		return nil
+	}
+
+	clone := *attributes
+	clone.AccountClassification = Pointer(attributes.AccountClassification)
+	clone.AccountMatchingOptOut = Pointer(attributes.AccountMatchingOptOut)
+	clone.AlternativeNames = Slice(attributes.AlternativeNames)
+	clone.Country = Pointer(attributes.Country)
+	clone.JointAccount = Pointer(attributes.JointAccount)
+	clone.Name = Slice(attributes.Name)
+	clone.OrganisationIdentification = OrganisationIdentification(attributes.OrganisationIdentification)
+	clone.PrivateIdentification = PrivateIdentification(attributes.PrivateIdentification)
+	clone.Status = Pointer(attributes.Status)
+	clone.Switched = Pointer(attributes.Switched)
+	clone.UserDefinedData = Slice(attributes.UserDefinedData)
+	return &clone
+}
+
+// OrganisationIdentification returns a deep copy of identification, or nil.
+func OrganisationIdentification(identification *model.OrganisationIdentification) *model.OrganisationIdentification {
+	if identification == nil {
+		return nil
+	}
+
+	clone := *identification
+	clone.Address = Slice(identification.Address)
+	clone.Actors = Slice(identification.Actors)
+	for i, actor := range identification.Actors {
+		clone.Actors[i].Name = Slice(actor.Name)
+	}
+	return &clone
+}
+
+// PrivateIdentification returns a deep copy of identification, or nil.
+func PrivateIdentification(identification *model.PrivateIdentification) *model.PrivateIdentification {
+	if identification == nil {
+		return nil
+	}
+
+	clone := *identification
+	clone.Address = Slice(identification.Address)
+	return &clone
+}
+
+func relationship(relationship *model.RelationshipData) *model.RelationshipData {
+	if relationship == nil {
+		return nil
+	}
+	return &model.RelationshipData{Data: Slice(relationship.Data)}
+}
+
+// Pointer returns a pointer to a copy of the value, or nil.
+func Pointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// Slice returns a copy of values, or nil.
+func Slice[T any](values []T) []T {
+	if values == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(values)), values...)
+}
@@ -0,0 +1,45 @@
+package clone
+
+import (
+	"form3-interview-accounts/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountIsDeepCopied(t *testing.T) {
+	country := "GB"
+	version := int64(0)
+	account := model.Account{
+		Version: &version,
+		Attributes: &model.AccountAttributes{
+			Country: &country,
+			Name:    []string{"Samantha Holder"},
+			OrganisationIdentification: &model.OrganisationIdentification{
+				Actors: []model.OrganisationActor{{Name: []string{"Jeff Page"}}},
+			},
+		},
+		Relationships: &model.AccountRelationships{
+			MasterAccount: &model.RelationshipData{Data: []model.ResourceIdentifier{{ID: "master"}}},
+		},
+	}
+
+	copied := Account(account)
+	*copied.Version = 1
+	*copied.Attributes.Country = "FR"
+	copied.Attributes.Name[0] = "Changed"
+	copied.Attributes.OrganisationIdentification.Actors[0].Name[0] = "Changed"
+	copied.Relationships.MasterAccount.Data[0].ID = "changed"
+
+	assert.Equal(t, int64(0), *account.Version)
+	assert.Equal(t, "GB", *account.Attributes.Country)
+	assert.Equal(t, []string{"Samantha Holder"}, account.Attributes.Name)
+	assert.Equal(t, []string{"Jeff Page"}, account.Attributes.OrganisationIdentification.Actors[0].Name)
+	assert.Equal(t, "master", account.Relationships.MasterAccount.Data[0].ID)
+}
+
+func TestNilIsCopiedAsNil(t *testing.T) {
+	assert.Nil(t, Attributes(nil))
+	assert.Nil(t, Pointer[string](nil))
+	assert.Nil(t, Slice[string](nil))
+}
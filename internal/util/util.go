@@ -2,30 +2,43 @@ package util
 
 import (
 	"encoding/json"
-	"fmt"
 	"io"
+	"net/url"
+	"strings"
 )
 
 func FromJsonToModel(reader io.ReadCloser, data interface{}) error {
 	return json.NewDecoder(reader).Decode(data)
 }
 
+// BuildUrl appends path and the escaped query parameters to hostname. Query parameters
+// are sorted by key so the same parameters always give the same URL. path is used as is,
+// untrusted segments have to be escaped with EscapePathSegment first.
 func BuildUrl(hostname string, path string, queryParameters map[string]string) string {
 	return hostname + path + buildQueryParams(queryParameters)
 }
 
 func buildQueryParams(queryParameters map[string]string) string {
-	if queryParameters == nil || len(queryParameters) == 0 {
+	if len(queryParameters) == 0 {
 		return ""
 	}
 
-	queryParamString := "?"
-
+	values := url.Values{}
 	for key, value := range queryParameters {
-		queryParamString += fmt.Sprintf("%s=%s&", key, value)
+		values.Set(key, value)
 	}
 
-	return queryParamString
+	return "?" + values.Encode()
+}
+
+// EscapePathSegment escapes segment so it stays a single path segment, including the
+// dot segments "." and ".." that would otherwise move the request to a parent path.
+func EscapePathSegment(segment string) string {
+	escaped := url.PathEscape(segment)
+	if escaped == "." || escaped == ".." {
+		return strings.Repeat("%2E", len(escaped))
+	}
+	return escaped
 }
 
 func GetSupportedCountries() []string {
@@ -3,6 +3,7 @@ package util
 import (
 	"form3-interview-accounts/model"
 	"io"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -22,5 +23,80 @@ func TestUrlBuilding(t *testing.T) {
 	m["k1"] = "test"
 	url := BuildUrl("test", "/hello", m)
 	assert.NotEmpty(t, url)
-	assert.Equal(t, url, "test/hello?k1=test&")
+	assert.Equal(t, url, "test/hello?k1=test")
+}
+
+func TestUrlBuildingIsEscapedAndSorted(t *testing.T) {
+	m := map[string]string{
+		"filter[country]": "GB",
+		"filter[bank_id]": "40&30=0",
+		"a b":             "c+d",
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "http://localhost:8080/v1/organisation/accounts?a+b=c%2Bd&filter%5Bbank_id%5D=40%2630%3D0&filter%5Bcountry%5D=GB",
+			BuildUrl("http://localhost:8080", "/v1/organisation/accounts", m))
+	}
+	assert.Equal(t, "test/hello", BuildUrl("test", "/hello", nil))
+	assert.Equal(t, "test/hello", BuildUrl("test", "/hello", map[string]string{}))
+}
+
+func TestPathSegmentEscaping(t *testing.T) {
+	assert.Equal(t, "0d209d7f-d07a-4542-947f-5885fddddae7", EscapePathSegment("0d209d7f-d07a-4542-947f-5885fddddae7"))
+	assert.Equal(t, "..%2Fhealth", EscapePathSegment("../health"))
+	assert.Equal(t, "id%3Fversion=1", EscapePathSegment("id?version=1"))
+	assert.Equal(t, "%2E", EscapePathSegment("."))
+	assert.Equal(t, "%2E%2E", EscapePathSegment(".."))
+}
+
+func FuzzBuildUrl(f *testing.F) {
+	f.Add("filter[country]", "GB")
+	f.Add("k1", "a&b=c")
+	f.Add("page[number]", "#fragment")
+	f.Add("", "%zz")
+	f.Fuzz(func(t *testing.T, key string, value string) {
+		builtUrl := BuildUrl("http://localhost:8080", "/v1/organisation/accounts", map[string]string{key: value})
+		parsed, err := url.Parse(builtUrl)
+		if err != nil {
+			t.Fatalf("built url %q does not parse: %s", builtUrl, err)
+		}
+		if parsed.Path != "/v1/organisation/accounts" || parsed.Fragment != "" {
+			t.Fatalf("built url %q changed the path to %q", builtUrl, parsed.Path)
+		}
+		query, err := url.ParseQuery(parsed.RawQuery)
+		if err != nil {
+			t.Fatalf("built url %q has an invalid query: %s", builtUrl, err)
+		}
+		if len(query) != 1 || len(query[key]) != 1 || query[key][0] != value {
+			t.Fatalf("built url %q does not round trip %q=%q, got %v", builtUrl, key, value, query)
+		}
+	})
+}
+
+func FuzzEscapePathSegment(f *testing.F) {
+	f.Add("0d209d7f-d07a-4542-947f-5885fddddae7")
+	f.Add("../health")
+	f.Add("..")
+	f.Add("id?version=1#x")
+	f.Fuzz(func(t *testing.T, segment string) {
+		escaped := EscapePathSegment(segment)
+		if strings.ContainsAny(escaped, "/?#") || escaped == "." || escaped == ".." {
+			t.Fatalf("segment %q escaped to unsafe %q", segment, escaped)
+		}
+		unescaped, err := url.PathUnescape(escaped)
+		if err != nil || unescaped != segment {
+			t.Fatalf("segment %q escaped to %q does not round trip, got %q", segment, escaped, unescaped)
+		}
+
+		builtUrl := BuildUrl("http://localhost:8080", "/v1/organisation/accounts/"+escaped, nil)
+		parsed, err := url.Parse(builtUrl)
+		if err != nil {
+			t.Fatalf("built url %q does not parse: %s", builtUrl, err)
+		}
+		if parsed.Path != "/v1/organisation/accounts/"+segment || parsed.RawQuery != "" || parsed.Fragment != "" {
+			t.Fatalf("segment %q altered the request url %q", segment, builtUrl)
+		}
+		if parsed.EscapedPath() != "/v1/organisation/accounts/"+escaped {
+			t.Fatalf("segment %q is sent as %q", segment, parsed.EscapedPath())
+		}
+	})
 }
@@ -0,0 +1,28 @@
+package validation
+
+import (
+	"form3-interview-accounts/model"
+	"regexp"
+)
+
+// bicFormat is the ISO 9362 structure: bank code, country code, location code and an
+// optional branch code.
+var bicFormat = regexp.MustCompile(`^[A-Z]{4}([A-Z]{2})[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+// validateBic checks the bic and, when the account country is known, that they match.
+func validateBic(country string, bic string) model.ValidationErrors {
+	if bic == "" {
+		return nil
+	}
+
+	match := bicFormat.FindStringSubmatch(bic)
+	if match == nil {
+		return model.ValidationErrors{attributeError("bic", model.ValidationCodeInvalid, "invalid bic %q, it must have 8 or 11 upper case letters and digits", bic)}
+	}
+
+	if bicCountry := match[1]; country != "" && bicCountry != country {
+		return model.ValidationErrors{attributeError("bic", model.ValidationCodeMismatch, "invalid bic %s, bic country %s does not match account country %s", bic, bicCountry, country)}
+	}
+
+	return nil
+}
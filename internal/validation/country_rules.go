@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"fmt"
+	"form3-interview-accounts/model"
+	"regexp"
+)
+
+// countryRule describes how the bank identifiers of an account look like in a country.
+type countryRule struct {
+	bankIDCode           model.BankIDCode
+	bankIDRequired       bool
+	bankIDAllowed        bool
+	bankIDPattern        *regexp.Regexp
+	bicRequired          bool
+	accountNumberPattern *regexp.Regexp
+	ibanAllowed          bool
+}
+
+var countryRules = map[string]countryRule{
+	"GB": {bankIDCode: model.BankIDCodeGBDSC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(6, 6), bicRequired: true, accountNumberPattern: digits(8, 8), ibanAllowed: true},
+	"AU": {bankIDCode: model.BankIDCodeAUBSB, bankIDAllowed: true, bankIDPattern: digits(6, 6), bicRequired: true, accountNumberPattern: regexp.MustCompile(`^[1-9][0-9]{5,9}$`)},
+	"BE": {bankIDCode: model.BankIDCodeBE, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(3, 3), accountNumberPattern: digits(7, 7), ibanAllowed: true},
+	"CA": {bankIDCode: model.BankIDCodeCACPA, bankIDAllowed: true, bankIDPattern: regexp.MustCompile(`^0[0-9]{8}$`), bicRequired: true, accountNumberPattern: digits(7, 12)},
+	"FR": {bankIDCode: model.BankIDCodeFR, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: alphanumerics(10, 10), accountNumberPattern: alphanumerics(10, 10), ibanAllowed: true},
+	"DE": {bankIDCode: model.BankIDCodeDEBLZ, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(8, 8), accountNumberPattern: digits(7, 7), ibanAllowed: true},
+	"GR": {bankIDCode: model.BankIDCodeGRBIC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(7, 7), accountNumberPattern: digits(16, 16), ibanAllowed: true},
+	"HK": {bankIDCode: model.BankIDCodeHKNCC, bankIDAllowed: true, bankIDPattern: digits(3, 3), bicRequired: true, accountNumberPattern: digits(9, 12)},
+	"IE": {bankIDCode: model.BankIDCodeGBDSC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(6, 6), bicRequired: true, accountNumberPattern: digits(8, 8), ibanAllowed: true},
+	"IT": {bankIDCode: model.BankIDCodeITNCC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(10, 11), accountNumberPattern: alphanumerics(12, 12), ibanAllowed: true},
+	"LU": {bankIDCode: model.BankIDCodeLULUX, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(3, 3), accountNumberPattern: alphanumerics(13, 13), ibanAllowed: true},
+	"NL": {bicRequired: true, accountNumberPattern: digits(10, 10), ibanAllowed: true},
+	"PL": {bankIDCode: model.BankIDCodePLKNR, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(8, 8), accountNumberPattern: digits(16, 16), ibanAllowed: true},
+	"PT": {bankIDCode: model.BankIDCodePTNCC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(8, 8), accountNumberPattern: digits(11, 11), ibanAllowed: true},
+	"ES": {bankIDCode: model.BankIDCodeESNCC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(8, 8), accountNumberPattern: digits(10, 10), ibanAllowed: true},
+	"CH": {bankIDCode: model.BankIDCodeCHBCC, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(5, 5), accountNumberPattern: alphanumerics(12, 12), ibanAllowed: true},
+	"US": {bankIDCode: model.BankIDCodeUSABA, bankIDRequired: true, bankIDAllowed: true, bankIDPattern: digits(9, 9), bicRequired: true, accountNumberPattern: digits(6, 17)},
+}
+
+func digits(min int, max int) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^[0-9]{%d,%d}$`, min, max))
+}
+
+func alphanumerics(min int, max int) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^[0-9A-Z]{%d,%d}$`, min, max))
+}
+
+// validateCountryRules returns every violation of the rules of country by attributes.
+// Countries without rules only have to be supported.
+func validateCountryRules(country string, attributes model.AccountAttributes) model.ValidationErrors {
+	rule, ok := countryRules[country]
+	if !ok {
+		return nil
+	}
+
+	var validationErrors model.ValidationErrors
+
+	if !rule.bankIDAllowed {
+		if attributes.BankID != "" {
+			validationErrors = append(validationErrors, attributeError("bank_id", model.ValidationCodeNotAllowed, "invalid bank_id, %s accounts must not have one", country))
+		}
+		if attributes.BankIDCode != "" {
+			validationErrors = append(validationErrors, attributeError("bank_id_code", model.ValidationCodeNotAllowed, "invalid bank_id_code, %s accounts must not have one", country))
+		}
+	} else {
+		if attributes.BankID == "" && rule.bankIDRequired {
+			validationErrors = append(validationErrors, attributeError("bank_id", model.ValidationCodeRequired, "invalid bank_id, bank_id is required for %s", country))
+		} else if attributes.BankID != "" && !rule.bankIDPattern.MatchString(attributes.BankID) {
+			validationErrors = append(validationErrors, attributeError("bank_id", model.ValidationCodeInvalid, "invalid bank_id %s for %s", attributes.BankID, country))
+		}
+
+		if attributes.BankIDCode.IsValid() && attributes.BankIDCode != rule.bankIDCode && (attributes.BankIDCode != "" || attributes.BankID != "" || rule.bankIDRequired) {
+			validationErrors = append(validationErrors, attributeError("bank_id_code", model.ValidationCodeInvalid, "invalid bank_id_code %q, %s requires %s", attributes.BankIDCode, country, rule.bankIDCode))
+		}
+	}
+
+	if attributes.Bic == "" && rule.bicRequired {
+		validationErrors = append(validationErrors, attributeError("bic", model.ValidationCodeRequired, "invalid bic, bic is required for %s", country))
+	}
+
+	if attributes.AccountNumber != "" && !rule.accountNumberPattern.MatchString(attributes.AccountNumber) {
+		validationErrors = append(validationErrors, attributeError("account_number", model.ValidationCodeInvalid, "invalid account_number %s for %s", attributes.AccountNumber, country))
+	}
+
+	if attributes.Iban != "" && !rule.ibanAllowed {
+		validationErrors = append(validationErrors, attributeError("iban", model.ValidationCodeNotAllowed, "invalid iban, %s accounts must not have one", country))
+	}
+
+	return validationErrors
+}
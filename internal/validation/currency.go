@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"form3-interview-accounts/model"
+	"strings"
+)
+
+// currencies holds the active ISO 4217 currency codes, without the testing and no
+// currency codes XTS and XXX.
+var currencies = toSet(strings.Fields(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB BOV
+	BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC CUC CUP CVE
+	CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD
+	HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW KWD KYD
+	KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV
+	MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB
+	RWF SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL THB TJS TMT
+	TND TOP TRY TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS VED VES VND VUV WST XAF
+	XAG XAU XBA XBB XBC XBD XCD XDR XOF XPD XPF XPT XSU XUA YER ZAR ZMW ZWL`))
+
+var defaultCurrencies = map[string]string{
+	"GB": "GBP", "AU": "AUD", "BE": "EUR", "CA": "CAD", "DK": "DKK", "FO": "DKK",
+	"GL": "DKK", "EE": "EUR", "FI": "EUR", "FR": "EUR", "DE": "EUR", "GR": "EUR",
+	"HK": "HKD", "IE": "EUR", "IT": "EUR", "LU": "EUR", "NL": "EUR", "PL": "PLN",
+	"PT": "EUR", "ES": "EUR", "SE": "SEK", "CH": "CHF", "US": "USD",
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// IsCurrency reports whether currency is an active ISO 4217 currency code.
+func IsCurrency(currency string) bool {
+	return currencies[currency]
+}
+
+// DefaultCurrency returns the currency accounts of a supported country are usually held in.
+func DefaultCurrency(country string) (string, bool) {
+	currency, ok := defaultCurrencies[country]
+	return currency, ok
+}
+
+func validateCurrency(currency string) model.ValidationErrors {
+	if currency == "" || IsCurrency(currency) {
+		return nil
+	}
+	return model.ValidationErrors{attributeError("base_currency", model.ValidationCodeInvalid, "invalid base_currency %q, it must be an ISO 4217 currency code", currency)}
+}
@@ -2,24 +2,165 @@ package validation
 
 import (
 	"fmt"
+	"form3-interview-accounts/iban"
 	"form3-interview-accounts/internal/util"
 	"form3-interview-accounts/model"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
+const dataPointer = "/data"
+const attributesPointer = dataPointer + "/attributes"
+
+// ValidateAccount checks an account before it is created. It returns every problem found
+// as model.ValidationErrors, or nil when the account is valid.
 func ValidateAccount(body model.AccountData) error {
-	if body.Data.Attributes == nil {
-		return fmt.Errorf("invalid body, attributes is missing")
+	validationErrors := validateResource(body.Data)
+
+	attributes := body.Data.Attributes
+	if attributes == nil {
+		validationErrors = append(validationErrors, model.ValidationError{
+			Pointer: attributesPointer, Code: model.ValidationCodeRequired, Message: "invalid body, attributes is missing",
+		})
+		return validationErrors
 	}
 
-	if body.Data.Attributes.Country == nil {
-		return fmt.Errorf("invalid country, country is missing")
+	country := ""
+	if attributes.Country == nil {
+		validationErrors = append(validationErrors, attributeError("country", model.ValidationCodeRequired, "invalid country, country is missing"))
+	} else if !isSupportedCountry(*attributes.Country) {
+		validationErrors = append(validationErrors, attributeError("country", model.ValidationCodeUnsupported, "invalid country %s", *attributes.Country))
+	} else {
+		country = *attributes.Country
+		validationErrors = append(validationErrors, validateCountryRules(country, *attributes)...)
 	}
 
-	for _, val := range util.GetSupportedCountries() {
-		if val == *body.Data.Attributes.Country {
-			return nil
+	validationErrors = append(validationErrors, validateEnums(*attributes)...)
+	validationErrors = append(validationErrors, validateCurrency(attributes.BaseCurrency)...)
+	validationErrors = append(validationErrors, validateIban(country, attributes.Iban)...)
+	validationErrors = append(validationErrors, validateBic(country, attributes.Bic)...)
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+	return nil
+}
+
+// validateResource checks the identifiers and type of the account resource.
+func validateResource(account model.Account) model.ValidationErrors {
+	var validationErrors model.ValidationErrors
+
+	if account.ID == "" {
+		validationErrors = append(validationErrors, resourceError("id", model.ValidationCodeRequired, "invalid id, id is missing"))
+	} else if !isUuid(account.ID) {
+		validationErrors = append(validationErrors, resourceError("id", model.ValidationCodeInvalid, "invalid id %s, it must be a UUID", account.ID))
+	}
+
+	if account.OrganisationID == "" {
+		validationErrors = append(validationErrors, resourceError("organisation_id", model.ValidationCodeRequired, "invalid organisation_id, organisation_id is missing"))
+	} else if !isUuid(account.OrganisationID) {
+		validationErrors = append(validationErrors, resourceError("organisation_id", model.ValidationCodeInvalid, "invalid organisation_id %s, it must be a UUID", account.OrganisationID))
+	}
+
+	if account.Type != model.AccountResourceType {
+		validationErrors = append(validationErrors, resourceError("type", model.ValidationCodeInvalid, "invalid type %q, it must be %s", account.Type, model.AccountResourceType))
+	}
+
+	return validationErrors
+}
+
+// isUuid accepts only the canonical hyphenated form expected by the API.
+func isUuid(value string) bool {
+	_, err := uuid.Parse(value)
+	return err == nil && len(value) == 36
+}
+
+func resourceError(field string, code string, format string, args ...interface{}) model.ValidationError {
+	return model.ValidationError{
+		Pointer: dataPointer + "/" + field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func attributeError(attribute string, code string, format string, args ...interface{}) model.ValidationError {
+	return model.ValidationError{
+		Pointer: attributesPointer + "/" + attribute,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// validateEnums catches enum values that were not unmarshalled but set in code.
+func validateEnums(attributes model.AccountAttributes) model.ValidationErrors {
+	var validationErrors model.ValidationErrors
+
+	if attributes.AccountClassification != nil && !attributes.AccountClassification.IsValid() {
+		validationErrors = append(validationErrors, attributeError("account_classification", model.ValidationCodeInvalid, "invalid account_classification %q", *attributes.AccountClassification))
+	}
+	if !attributes.BankIDCode.IsValid() {
+		validationErrors = append(validationErrors, attributeError("bank_id_code", model.ValidationCodeInvalid, "invalid bank_id_code %q", attributes.BankIDCode))
+	}
+	if attributes.Status != nil && !attributes.Status.IsValid() {
+		validationErrors = append(validationErrors, attributeError("status", model.ValidationCodeInvalid, "invalid status %q", *attributes.Status))
+	}
+
+	return validationErrors
+}
+
+// validateIban checks the iban and, when the account country is known, that they match.
+func validateIban(country string, accountIban string) model.ValidationErrors {
+	if accountIban == "" {
+		return nil
+	}
+
+	var validationErrors model.ValidationErrors
+	if err := iban.Validate(accountIban); err != nil {
+		validationErrors = append(validationErrors, attributeError("iban", model.ValidationCodeInvalid, "invalid iban, %s", err))
+	}
+	if ibanCountry := iban.Country(accountIban); country != "" && ibanCountry != country {
+		validationErrors = append(validationErrors, attributeError("iban", model.ValidationCodeMismatch, "invalid iban, iban country %s does not match account country %s", ibanCountry, country))
+	}
+
+	return validationErrors
+}
+
+func ValidateAccountFilter(filter model.AccountFilter) error {
+	queryParameters := filter.QueryParameters()
+	names := make([]string, 0, len(queryParameters))
+	for name := range queryParameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if value := queryParameters[name]; strings.TrimSpace(value) != value {
+			return fmt.Errorf("invalid filter %s, value %q has surrounding whitespace", name, value)
 		}
 	}
 
-	return fmt.Errorf("invalid country %s", *body.Data.Attributes.Country)
+	if filter.Country != "" && !isSupportedCountry(filter.Country) {
+		return fmt.Errorf("invalid filter country %s", filter.Country)
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return fmt.Errorf("invalid filter status %s", filter.Status)
+	}
+
+	if !filter.BankIDCode.IsValid() {
+		return fmt.Errorf("invalid filter bank_id_code %s", filter.BankIDCode)
+	}
+
+	return nil
+}
+
+func isSupportedCountry(country string) bool {
+	for _, val := range util.GetSupportedCountries() {
+		if val == country {
+			return true
+		}
+	}
+	return false
 }
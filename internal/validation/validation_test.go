@@ -0,0 +1,236 @@
+package validation
+
+import (
+	"errors"
+	"form3-interview-accounts/internal/util"
+	"form3-interview-accounts/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getAccountData(country string, attributes model.AccountAttributes) model.AccountData {
+	attributes.Country = &country
+	return model.AccountData{
+		Data: model.Account{
+			ID:             "0d209d7f-d07a-4542-947f-5885fddddae7",
+			OrganisationID: "ba61483c-d5c5-4f50-ae81-6b8c039bea43",
+			Type:           "accounts",
+			Attributes:     &attributes,
+		},
+	}
+}
+
+func TestValidateAccountCountry(t *testing.T) {
+	err := ValidateAccount(model.AccountData{})
+	assert.NotEmpty(t, err, "Error is empty for missing attributes")
+
+	err = ValidateAccount(model.AccountData{Data: model.Account{Attributes: &model.AccountAttributes{}}})
+	assert.NotEmpty(t, err, "Error is empty for missing country")
+
+	err = ValidateAccount(getAccountData("XX", model.AccountAttributes{}))
+	assert.NotEmpty(t, err, "Error is empty for unsupported country")
+
+	err = ValidateAccount(getAccountData("SE", model.AccountAttributes{}))
+	assert.Empty(t, err, "Error is not empty for country without rules")
+}
+
+func TestValidateAccountCountryRules(t *testing.T) {
+	validAccounts := []struct {
+		country    string
+		attributes model.AccountAttributes
+	}{
+		{"GB", model.AccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "41426819", Iban: "GB16NWBK40030041426819"}},
+		{"AU", model.AccountAttributes{BankID: "013012", BankIDCode: "AUBSB", Bic: "ANZBAU3M", AccountNumber: "123456789"}},
+		{"AU", model.AccountAttributes{Bic: "ANZBAU3M"}},
+		{"BE", model.AccountAttributes{BankID: "539", BankIDCode: "BE", AccountNumber: "0075470"}},
+		{"CA", model.AccountAttributes{BankID: "012345678", BankIDCode: "CACPA", Bic: "ROYCCAT2", AccountNumber: "1234567"}},
+		{"DE", model.AccountAttributes{BankID: "37040044", BankIDCode: "DEBLZ", AccountNumber: "0532013"}},
+		{"NL", model.AccountAttributes{Bic: "ABNANL2A", AccountNumber: "0417164300"}},
+		{"US", model.AccountAttributes{BankID: "026009593", BankIDCode: "USABA", Bic: "BOFAUS3N", AccountNumber: "12345678"}},
+	}
+
+	for _, account := range validAccounts {
+		err := ValidateAccount(getAccountData(account.country, account.attributes))
+		assert.Empty(t, err, "Error is not empty for valid %s account %#v", account.country, account.attributes)
+	}
+}
+
+func TestValidateAccountReturnsAllCountryRuleViolations(t *testing.T) {
+	err := ValidateAccount(getAccountData("GB", model.AccountAttributes{BankID: "4003", BankIDCode: "GBDS", AccountNumber: "123"}))
+	assert.NotEmpty(t, err, "Error is empty")
+	assert.Contains(t, err.Error(), "invalid bank_id 4003")
+	assert.Contains(t, err.Error(), `invalid bank_id_code "GBDS"`)
+	assert.Contains(t, err.Error(), "bic is required")
+	assert.Contains(t, err.Error(), "invalid account_number 123")
+
+	err = ValidateAccount(getAccountData("GB", model.AccountAttributes{Bic: "NWBKGB22"}))
+	assert.Contains(t, err.Error(), "bank_id is required")
+	assert.Contains(t, err.Error(), "requires GBDSC")
+
+	err = ValidateAccount(getAccountData("AU", model.AccountAttributes{Bic: "ANZBAU3M", BankIDCode: "GBDSC", Iban: "GB16NWBK40030041426819"}))
+	assert.Contains(t, err.Error(), "requires AUBSB")
+	assert.Contains(t, err.Error(), "AU accounts must not have one")
+
+	err = ValidateAccount(getAccountData("NL", model.AccountAttributes{Bic: "ABNANL2A", BankID: "123"}))
+	assert.Contains(t, err.Error(), "invalid bank_id, NL accounts must not have one")
+
+	err = ValidateAccount(getAccountData("US", model.AccountAttributes{BankID: "26009593", BankIDCode: "USABA", Bic: "BOFAUS3N"}))
+	assert.Contains(t, err.Error(), "invalid bank_id 26009593 for US")
+}
+
+func TestValidateAccountIban(t *testing.T) {
+	gbAttributes := model.AccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22"}
+
+	gbAttributes.Iban = "GB16NWBK40030041426819"
+	err := ValidateAccount(getAccountData("GB", gbAttributes))
+	assert.Empty(t, err, "Error is not empty for valid iban")
+
+	gbAttributes.Iban = "GB17NWBK40030041426819"
+	err = ValidateAccount(getAccountData("GB", gbAttributes))
+	assert.Contains(t, err.Error(), "invalid iban checksum")
+
+	gbAttributes.Iban = "GB16NWBK4003004142681"
+	err = ValidateAccount(getAccountData("GB", gbAttributes))
+	assert.Contains(t, err.Error(), "invalid iban length")
+
+	gbAttributes.Iban = "DE89370400440532013000"
+	err = ValidateAccount(getAccountData("GB", gbAttributes))
+	assert.Contains(t, err.Error(), "iban country DE does not match account country GB")
+
+	err = ValidateAccount(getAccountData("SE", model.AccountAttributes{Iban: "SE4550000000058398257466"}))
+	assert.Empty(t, err, "Error is not empty for valid iban of country without rules")
+}
+
+func TestValidateAccountBic(t *testing.T) {
+	gbAttributes := model.AccountAttributes{BankID: "400300", BankIDCode: "GBDSC"}
+
+	for _, bic := range []string{"NWBKGB22", "NWBKGB2L123", "NWBKGB22XXX"} {
+		gbAttributes.Bic = bic
+		err := ValidateAccount(getAccountData("GB", gbAttributes))
+		assert.Empty(t, err, "Error is not empty for valid bic %s", bic)
+	}
+
+	for _, bic := range []string{"NWBKGB2", "NWBKGB221", "NWBKGB22XXXX", "nwbkgb22", "NWB1GB22", "NWBKG122"} {
+		gbAttributes.Bic = bic
+		err := ValidateAccount(getAccountData("GB", gbAttributes))
+		assert.NotEmpty(t, err, "Error is empty for invalid bic %s", bic)
+		assert.Contains(t, err.Error(), "invalid bic", "Error is not scoped to the bic")
+	}
+
+	gbAttributes.Bic = "DEUTDEFF"
+	err := ValidateAccount(getAccountData("GB", gbAttributes))
+	assert.Contains(t, err.Error(), "bic country DE does not match account country GB")
+}
+
+func TestValidateAccountReturnsFieldAddressableErrors(t *testing.T) {
+	accountData := getAccountData("", model.AccountAttributes{Iban: "GB17NWBK40030041426819", Bic: "nwbkgb22"})
+	accountData.Data.Attributes.Country = nil
+	err := ValidateAccount(accountData)
+
+	var validationErrors model.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, model.ValidationErrors{
+		{Pointer: "/data/attributes/country", Code: model.ValidationCodeRequired, Message: "invalid country, country is missing"},
+		{Pointer: "/data/attributes/iban", Code: model.ValidationCodeInvalid, Message: "invalid iban, invalid iban checksum: GB17NWBK40030041426819"},
+		{Pointer: "/data/attributes/bic", Code: model.ValidationCodeInvalid, Message: `invalid bic "nwbkgb22", it must have 8 or 11 upper case letters and digits`},
+	}, validationErrors)
+
+	err = ValidateAccount(getAccountData("GB", model.AccountAttributes{BankID: "4003", Iban: "DE89370400440532013000"}))
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, []string{
+		"/data/attributes/bank_id",
+		"/data/attributes/bank_id_code",
+		"/data/attributes/bic",
+		"/data/attributes/iban",
+	}, validationErrors.Pointers())
+
+	err = ValidateAccount(model.AccountData{})
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, []string{"/data/id", "/data/organisation_id", "/data/type", "/data/attributes"}, validationErrors.Pointers())
+}
+
+func TestValidateAccountResource(t *testing.T) {
+	accountData := getAccountData("GB", model.AccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22"})
+	accountData.Data.ID = "0d209d7f-d07a-4542-947f-5885fddddae"
+	accountData.Data.OrganisationID = "{ba61483c-d5c5-4f50-ae81-6b8c039bea43}"
+	accountData.Data.Type = "account"
+
+	err := ValidateAccount(accountData)
+	var validationErrors model.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, model.ValidationErrors{
+		{Pointer: "/data/id", Code: model.ValidationCodeInvalid, Message: "invalid id 0d209d7f-d07a-4542-947f-5885fddddae, it must be a UUID"},
+		{Pointer: "/data/organisation_id", Code: model.ValidationCodeInvalid, Message: "invalid organisation_id {ba61483c-d5c5-4f50-ae81-6b8c039bea43}, it must be a UUID"},
+		{Pointer: "/data/type", Code: model.ValidationCodeInvalid, Message: `invalid type "account", it must be accounts`},
+	}, validationErrors)
+
+	accountData.Data.ID = ""
+	accountData.Data.OrganisationID = ""
+	accountData.Data.Type = "accounts"
+	err = ValidateAccount(accountData)
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, []string{"/data/id", "/data/organisation_id"}, validationErrors.Pointers())
+	assert.Equal(t, model.ValidationCodeRequired, validationErrors[0].Code)
+}
+
+func TestValidateValidAccountReturnsNil(t *testing.T) {
+	err := ValidateAccount(getAccountData("GB", model.AccountAttributes{BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22"}))
+	assert.Nil(t, err, "Error is not nil")
+}
+
+func TestValidateAccountEnums(t *testing.T) {
+	classification := model.AccountClassification("Personel")
+	status := model.AccountStatus("confirmed ")
+	err := ValidateAccount(getAccountData("SE", model.AccountAttributes{
+		AccountClassification: &classification,
+		Status:                &status,
+		BankIDCode:            "SEBGC",
+	}))
+
+	var validationErrors model.ValidationErrors
+	assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors")
+	assert.Equal(t, []string{
+		"/data/attributes/account_classification",
+		"/data/attributes/bank_id_code",
+		"/data/attributes/status",
+	}, validationErrors.Pointers())
+
+	classification = model.AccountClassificationBusiness
+	status = model.AccountStatusConfirmed
+	err = ValidateAccount(getAccountData("SE", model.AccountAttributes{AccountClassification: &classification, Status: &status}))
+	assert.Nil(t, err, "Error is not nil")
+}
+
+func TestValidateAccountCurrency(t *testing.T) {
+	for _, currency := range []string{"", "GBP", "EUR", "USD", "CHF"} {
+		err := ValidateAccount(getAccountData("SE", model.AccountAttributes{BaseCurrency: currency}))
+		assert.Nil(t, err, "Error is not nil for currency %q", currency)
+	}
+
+	for _, currency := range []string{"gbp", "GB", "XXX", "ABC", "EURO"} {
+		err := ValidateAccount(getAccountData("SE", model.AccountAttributes{BaseCurrency: currency}))
+		var validationErrors model.ValidationErrors
+		assert.True(t, errors.As(err, &validationErrors), "Error is not ValidationErrors for currency %q", currency)
+		assert.Equal(t, []string{"/data/attributes/base_currency"}, validationErrors.Pointers())
+	}
+}
+
+func TestDefaultCurrency(t *testing.T) {
+	currency, ok := DefaultCurrency("GB")
+	assert.True(t, ok)
+	assert.Equal(t, "GBP", currency)
+
+	currency, ok = DefaultCurrency("FO")
+	assert.True(t, ok)
+	assert.Equal(t, "DKK", currency)
+
+	_, ok = DefaultCurrency("XX")
+	assert.False(t, ok)
+
+	for _, country := range util.GetSupportedCountries() {
+		currency, ok := DefaultCurrency(country)
+		assert.True(t, ok, "No default currency for %s", country)
+		assert.True(t, IsCurrency(currency), "Default currency %s of %s is unknown", currency, country)
+	}
+}
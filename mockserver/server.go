@@ -0,0 +1,238 @@
+// Package mockserver emulates the account API over HTTP, keeping the accounts in memory,
+// so AccountApi can be exercised end to end without the docker-compose stack.
+package mockserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"form3-interview-accounts/api"
+	"form3-interview-accounts/fake"
+	"form3-interview-accounts/model"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+const healthyPath = "/v1/health"
+const accountsPath = "/v1/organisation/accounts"
+const jsonApiContentType = "application/vnd.api+json"
+
+type accountResponse struct {
+	Data  model.Account `json:"data"`
+	Links model.Links   `json:"links"`
+}
+
+type errorResponse struct {
+	ErrorMessage string `json:"error_message"`
+}
+
+type handler struct {
+	accounts *fake.AccountOperations
+}
+
+// NewServer starts an httptest server backed by a new in-memory store. Callers must Close it.
+func NewServer() *httptest.Server {
+	return httptest.NewServer(NewHandler(fake.NewAccountOperations()))
+}
+
+// NewHandler serves the account API endpoints from accounts. Failures and latency set on
+// accounts apply to the HTTP calls too.
+func NewHandler(accounts *fake.AccountOperations) http.Handler {
+	h := handler{accounts: accounts}
+	mux := http.NewServeMux()
+	mux.HandleFunc(healthyPath, h.health)
+	mux.HandleFunc(accountsPath, h.accountCollection)
+	mux.HandleFunc(accountsPath+"/", h.account)
+	return mux
+}
+
+func (h handler) health(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+	if err := h.accounts.IsHealthyWithContext(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJson(w, http.StatusOK, model.HealthyData{Status: "up"})
+}
+
+func (h handler) accountCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listAccounts(w, r)
+	case http.MethodPost:
+		h.createAccount(w, r)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (h handler) account(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, accountsPath+"/")
+	if id == "" || strings.Contains(id, "/") {
+		writeErrorMessage(w, http.StatusNotFound, "resource not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		account, err := h.accounts.GetAccountWithContext(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeAccount(w, http.StatusOK, account)
+	case http.MethodPatch:
+		h.patchAccount(w, r, id)
+	case http.MethodDelete:
+		version, err := strconv.Atoi(r.URL.Query().Get("version"))
+		if err != nil {
+			writeErrorMessage(w, http.StatusBadRequest, "invalid version number")
+			return
+		}
+		if err = h.accounts.DeleteAccountWithContext(r.Context(), id, version); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (h handler) listAccounts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := model.AccountFilter{
+		BankID:        query.Get("filter[bank_id]"),
+		BankIDCode:    model.BankIDCode(query.Get("filter[bank_id_code]")),
+		AccountNumber: query.Get("filter[account_number]"),
+		Iban:          query.Get("filter[iban]"),
+		Country:       query.Get("filter[country]"),
+		CustomerID:    query.Get("filter[customer_id]"),
+		Status:        model.AccountStatus(query.Get("filter[status]")),
+	}
+
+	page := model.PageRequest{}
+	var err error
+	if size := query.Get("page[size]"); size != "" {
+		if page.Size, err = strconv.Atoi(size); err != nil || page.Size < 0 {
+			writeErrorMessage(w, http.StatusBadRequest, "invalid page size")
+			return
+		}
+	}
+
+	number := query.Get("page[number]")
+	switch number {
+	case "", "first":
+	case "last":
+		page.Number, err = h.lastPage(r, filter, page)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	default:
+		if page.Number, err = strconv.Atoi(number); err != nil || page.Number < 0 {
+			writeErrorMessage(w, http.StatusBadRequest, "invalid page number")
+			return
+		}
+	}
+
+	data, err := h.accounts.GetAccountsPageWithContext(r.Context(), filter, page)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJson(w, http.StatusOK, data)
+}
+
+// lastPage walks the pages until the one without a next link.
+func (h handler) lastPage(r *http.Request, filter model.AccountFilter, page model.PageRequest) (int, error) {
+	for {
+		data, err := h.accounts.GetAccountsPageWithContext(r.Context(), filter, page)
+		if err != nil {
+			return 0, err
+		}
+		if data.Links.Next == "" {
+			return page.Number, nil
+		}
+		page.Number++
+	}
+}
+
+func (h handler) createAccount(w http.ResponseWriter, r *http.Request) {
+	var accountData model.AccountData
+	if err := json.NewDecoder(r.Body).Decode(&accountData); err != nil {
+		writeErrorMessage(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+
+	account, err := h.accounts.CreateAccountWithContext(r.Context(), accountData)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeAccount(w, http.StatusCreated, account)
+}
+
+func (h handler) patchAccount(w http.ResponseWriter, r *http.Request, id string) {
+	var patchData model.AccountPatchData
+	if err := json.NewDecoder(r.Body).Decode(&patchData); err != nil {
+		writeErrorMessage(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+	if patchData.Data.ID != "" && patchData.Data.ID != id {
+		writeErrorMessage(w, http.StatusBadRequest, "id in body does not match id in path")
+		return
+	}
+	if patchData.Data.Version == nil {
+		writeErrorMessage(w, http.StatusBadRequest, "version in body is required")
+		return
+	}
+
+	var attributes model.AccountAttributesPatch
+	if patchData.Data.Attributes != nil {
+		attributes = *patchData.Data.Attributes
+	}
+	account, err := h.accounts.PatchAccountWithContext(r.Context(), id, int(*patchData.Data.Version), attributes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeAccount(w, http.StatusOK, account)
+}
+
+func writeAccount(w http.ResponseWriter, status int, account *model.Account) {
+	writeJson(w, status, accountResponse{
+		Data:  *account,
+		Links: model.Links{Self: accountsPath + "/" + account.ID},
+	})
+}
+
+// writeError answers with the status of an *api.APIError, or a 400 for other errors such
+// as an invalid filter.
+func writeError(w http.ResponseWriter, err error) {
+	var apiError *api.APIError
+	if errors.As(err, &apiError) {
+		writeErrorMessage(w, apiError.StatusCode, apiError.ErrorMessage)
+		return
+	}
+	writeErrorMessage(w, http.StatusBadRequest, err.Error())
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeErrorMessage(w, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func writeErrorMessage(w http.ResponseWriter, status int, message string) {
+	writeJson(w, status, errorResponse{ErrorMessage: message})
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", jsonApiContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,140 @@
+package mockserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"form3-interview-accounts/api"
+	"form3-interview-accounts/fake"
+	"form3-interview-accounts/mockserver"
+	"form3-interview-accounts/model"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func getAccountApi(t *testing.T) (*api.AccountApi, *httptest.Server) {
+	server := mockserver.NewServer()
+	t.Cleanup(server.Close)
+	accountApi, err := api.NewAccountApi(server.URL)
+	assert.Nil(t, err, "Error is not empty")
+	return accountApi, server
+}
+
+func TestHealth(t *testing.T) {
+	accountApi, _ := getAccountApi(t)
+
+	assert.Nil(t, accountApi.IsHealthy(), "Error is not empty")
+}
+
+func TestAccountLifecycle(t *testing.T) {
+	accountApi, _ := getAccountApi(t)
+	accountData := fake.NewAccountData("GB")
+
+	created, err := accountApi.CreateAccount(accountData)
+	assert.Nil(t, err, "Error is not empty")
+	assert.Equal(t, int64(0), *created.Version)
+
+	fetched, err := accountApi.GetAccount(accountData.Data.ID)
+	assert.Nil(t, err, "Error is not empty")
+	assert.Equal(t, created.ID, fetched.ID)
+
+	patched, err := accountApi.PatchAccount(accountData.Data.ID, 0, model.AccountAttributesPatch{CustomerID: "customer-1"})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Equal(t, int64(1), *patched.Version)
+	assert.Equal(t, "customer-1", patched.Attributes.CustomerID)
+
+	err = accountApi.DeleteAccount(accountData.Data.ID, 0)
+	assert.True(t, api.IsConflict(err), "Error is not a conflict")
+
+	err = accountApi.DeleteAccount(accountData.Data.ID, 1)
+	assert.Nil(t, err, "Error is not empty")
+
+	_, err = accountApi.GetAccount(accountData.Data.ID)
+	assert.True(t, api.IsNotFound(err), "Error is not a not found")
+}
+
+func TestCreateDuplicateAccount(t *testing.T) {
+	accountApi, _ := getAccountApi(t)
+	accountData := fake.NewAccountData("GB")
+	_, _ = accountApi.CreateAccount(accountData)
+
+	_, err := accountApi.CreateAccount(accountData)
+	assert.True(t, api.IsConflict(err), "Error is not a conflict")
+}
+
+func TestListAccountsWithFilterAndPaging(t *testing.T) {
+	accountApi, _ := getAccountApi(t)
+	for i := 0; i < 3; i++ {
+		_, _ = accountApi.CreateAccount(fake.NewAccountData("GB"))
+	}
+	_, _ = accountApi.CreateAccount(fake.NewAccountData("FR"))
+
+	accounts, err := accountApi.GetAccounts(model.AccountFilter{Country: "FR"})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Len(t, accounts, 1)
+
+	page, err := accountApi.GetAccountsPage(model.AccountFilter{Country: "GB"}, model.PageRequest{Number: 0, Size: 2})
+	assert.Nil(t, err, "Error is not empty")
+	assert.Len(t, page.Data, 2)
+	assert.NotEmpty(t, page.Links.Next)
+
+	var count int
+	iterator := accountApi.ListAccounts(context.Background(), model.AccountFilter{Country: "GB"}, 2)
+	for iterator.Next() {
+		count++
+	}
+	assert.Nil(t, iterator.Err(), "Error is not empty")
+	assert.Equal(t, 3, count)
+}
+
+func TestListAccountsWithOutOfRangePage(t *testing.T) {
+	server := httptest.NewServer(mockserver.NewHandler(fake.NewAccountOperations()))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/organisation/accounts?page[number]=92233720368547759&page[size]=100")
+	assert.Nil(t, err, "Error is not empty")
+	defer resp.Body.Close()
+	var page model.AccountsData
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&page), "Error is not empty")
+	assert.Empty(t, page.Data)
+
+	resp, err = http.Get(server.URL + "/v1/organisation/accounts?page[number]=-1")
+	assert.Nil(t, err, "Error is not empty")
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.JSONEq(t, `{"error_message": "invalid page number"}`, string(body))
+}
+
+func TestErrorBody(t *testing.T) {
+	server := httptest.NewServer(mockserver.NewHandler(fake.NewAccountOperations()))
+	defer server.Close()
+	id := uuid.NewString()
+
+	resp, err := http.Get(server.URL + "/v1/organisation/accounts/" + id)
+	assert.Nil(t, err, "Error is not empty")
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.JSONEq(t, `{"error_message": "record `+id+` does not exist"}`, string(body))
+}
+
+func TestInjectedFailure(t *testing.T) {
+	accounts := fake.NewAccountOperations()
+	accounts.SetFailure(api.OperationHealth, fake.NewAPIError(http.StatusServiceUnavailable, "service unavailable"))
+	server := httptest.NewServer(mockserver.NewHandler(accounts))
+	defer server.Close()
+	accountApi, _ := api.NewAccountApi(server.URL)
+
+	err := accountApi.IsHealthy()
+	var apiError *api.APIError
+	assert.True(t, errors.As(err, &apiError), "Error is not an APIError")
+	assert.Equal(t, http.StatusServiceUnavailable, apiError.StatusCode)
+	assert.Equal(t, "service unavailable", apiError.ErrorMessage)
+}
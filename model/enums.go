@@ -0,0 +1,118 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type AccountClassification string
+
+const (
+	AccountClassificationPersonal AccountClassification = "Personal"
+	AccountClassificationBusiness AccountClassification = "Business"
+)
+
+type AccountStatus string
+
+const (
+	AccountStatusPending   AccountStatus = "pending"
+	AccountStatusConfirmed AccountStatus = "confirmed"
+	AccountStatusFailed    AccountStatus = "failed"
+)
+
+// BankIDCode identifies the national clearing system of the bank_id of an account.
+type BankIDCode string
+
+const (
+	BankIDCodeGBDSC BankIDCode = "GBDSC"
+	BankIDCodeAUBSB BankIDCode = "AUBSB"
+	BankIDCodeBE    BankIDCode = "BE"
+	BankIDCodeCACPA BankIDCode = "CACPA"
+	BankIDCodeFR    BankIDCode = "FR"
+	BankIDCodeDEBLZ BankIDCode = "DEBLZ"
+	BankIDCodeGRBIC BankIDCode = "GRBIC"
+	BankIDCodeHKNCC BankIDCode = "HKNCC"
+	BankIDCodeITNCC BankIDCode = "ITNCC"
+	BankIDCodeLULUX BankIDCode = "LULUX"
+	BankIDCodePLKNR BankIDCode = "PLKNR"
+	BankIDCodePTNCC BankIDCode = "PTNCC"
+	BankIDCodeESNCC BankIDCode = "ESNCC"
+	BankIDCodeCHBCC BankIDCode = "CHBCC"
+	BankIDCodeUSABA BankIDCode = "USABA"
+)
+
+var accountClassifications = []AccountClassification{AccountClassificationPersonal, AccountClassificationBusiness}
+
+var accountStatuses = []AccountStatus{AccountStatusPending, AccountStatusConfirmed, AccountStatusFailed}
+
+var bankIDCodes = []BankIDCode{
+	BankIDCodeGBDSC, BankIDCodeAUBSB, BankIDCodeBE, BankIDCodeCACPA, BankIDCodeFR,
+	BankIDCodeDEBLZ, BankIDCodeGRBIC, BankIDCodeHKNCC, BankIDCodeITNCC, BankIDCodeLULUX,
+	BankIDCodePLKNR, BankIDCodePTNCC, BankIDCodeESNCC, BankIDCodeCHBCC, BankIDCodeUSABA,
+}
+
+func (classification AccountClassification) IsValid() bool {
+	return contains(accountClassifications, classification)
+}
+
+func (classification AccountClassification) MarshalJSON() ([]byte, error) {
+	return marshalEnum("account classification", classification, classification.IsValid())
+}
+
+func (classification *AccountClassification) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("account classification", data, classification)
+}
+
+func (status AccountStatus) IsValid() bool {
+	return contains(accountStatuses, status)
+}
+
+func (status AccountStatus) MarshalJSON() ([]byte, error) {
+	return marshalEnum("account status", status, status.IsValid())
+}
+
+func (status *AccountStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("account status", data, status)
+}
+
+// IsValid reports whether code is a known bank ID code. The empty code is valid as
+// bank_id_code is optional for some countries.
+func (code BankIDCode) IsValid() bool {
+	return code == "" || contains(bankIDCodes, code)
+}
+
+func (code BankIDCode) MarshalJSON() ([]byte, error) {
+	return marshalEnum("bank id code", code, code.IsValid())
+}
+
+func (code *BankIDCode) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum("bank id code", data, code)
+}
+
+func contains[T comparable](values []T, value T) bool {
+	for _, val := range values {
+		if val == value {
+			return true
+		}
+	}
+	return false
+}
+
+func marshalEnum[T ~string](name string, value T, valid bool) ([]byte, error) {
+	if !valid {
+		return nil, fmt.Errorf("unknown %s %q", name, string(value))
+	}
+	return json.Marshal(string(value))
+}
+
+// unmarshalEnum keeps unknown values, so accounts read from the API decode even when it
+// returns values added after this client. They are still rejected when marshalling.
+func unmarshalEnum[T ~string](name string, data []byte, target *T) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid %s %s. Error: %w", name, string(data), err)
+	}
+
+	*target = T(value)
+	return nil
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumsUnmarshalKnownValues(t *testing.T) {
+	var attributes AccountAttributes
+	err := json.Unmarshal([]byte(`{"account_classification": "Business", "status": "pending", "bank_id_code": "USABA"}`), &attributes)
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, AccountClassificationBusiness, *attributes.AccountClassification)
+	assert.Equal(t, AccountStatusPending, *attributes.Status)
+	assert.Equal(t, BankIDCodeUSABA, attributes.BankIDCode)
+}
+
+func TestEnumsKeepUnknownValues(t *testing.T) {
+	var attributes AccountAttributes
+	err := json.Unmarshal([]byte(`{"account_classification": "Personel", "status": "closed", "bank_id_code": "DKNCC"}`), &attributes)
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, AccountClassification("Personel"), *attributes.AccountClassification)
+	assert.Equal(t, AccountStatus("closed"), *attributes.Status)
+	assert.Equal(t, BankIDCode("DKNCC"), attributes.BankIDCode)
+	assert.False(t, attributes.BankIDCode.IsValid())
+}
+
+func TestEnumsRejectNonStrings(t *testing.T) {
+	invalidAttributes := []string{
+		`{"account_classification": 1}`,
+		`{"status": 1}`,
+		`{"bank_id_code": ["GBDSC"]}`,
+	}
+
+	for _, invalid := range invalidAttributes {
+		var attributes AccountAttributes
+		err := json.Unmarshal([]byte(invalid), &attributes)
+		assert.NotEmpty(t, err, "Error is empty for %s", invalid)
+	}
+}
+
+func TestEnumsMarshalling(t *testing.T) {
+	classification := AccountClassificationPersonal
+	status := AccountStatusConfirmed
+	marshaledData, err := json.Marshal(AccountAttributes{AccountClassification: &classification, Status: &status, BankIDCode: BankIDCodeGBDSC})
+	assert.Empty(t, err, "Error is not empty")
+	assert.JSONEq(t, `{"account_classification": "Personal", "status": "confirmed", "bank_id_code": "GBDSC", "name": null}`, string(marshaledData))
+
+	marshaledData, err = json.Marshal(AccountAttributes{})
+	assert.Empty(t, err, "Error is not empty")
+	assert.JSONEq(t, `{"name": null}`, string(marshaledData))
+
+	status = AccountStatus("confirmed ")
+	_, err = json.Marshal(AccountAttributes{Status: &status})
+	assert.NotEmpty(t, err, "Error is empty for unknown status")
+
+	_, err = json.Marshal(AccountAttributes{BankIDCode: "GBDS"})
+	assert.NotEmpty(t, err, "Error is empty for unknown bank id code")
+}
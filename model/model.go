@@ -1,11 +1,63 @@
 package model
 
+import "time"
+
+// AccountResourceType is the type of every account resource.
+const AccountResourceType = "accounts"
+
 type HealthyData struct {
 	Status string `json:"status,omitempty"`
 }
 
 type AccountsData struct {
-	Data []Account `json:"data,omitempty"`
+	Data  []Account `json:"data,omitempty"`
+	Links *Links    `json:"links,omitempty"`
+}
+
+type Links struct {
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Self  string `json:"self,omitempty"`
+}
+
+// AccountFilter narrows down a listing of accounts to those matching every non-empty field.
+type AccountFilter struct {
+	BankID        string
+	BankIDCode    BankIDCode
+	AccountNumber string
+	Iban          string
+	Country       string
+	CustomerID    string
+	Status        AccountStatus
+}
+
+// QueryParameters renders the filter as the filter[...] query parameters of the account API.
+func (filter AccountFilter) QueryParameters() map[string]string {
+	queryParameters := make(map[string]string)
+	addFilter := func(name string, value string) {
+		if value != "" {
+			queryParameters["filter["+name+"]"] = value
+		}
+	}
+
+	addFilter("bank_id", filter.BankID)
+	addFilter("bank_id_code", string(filter.BankIDCode))
+	addFilter("account_number", filter.AccountNumber)
+	addFilter("iban", filter.Iban)
+	addFilter("country", filter.Country)
+	addFilter("customer_id", filter.CustomerID)
+	addFilter("status", string(filter.Status))
+
+	return queryParameters
+}
+
+// PageRequest selects a page of a listing. Zero values leave the choice to the API,
+// which starts at page 0 with a page size of 100.
+type PageRequest struct {
+	Number int
+	Size   int
 }
 
 type AccountData struct {
@@ -13,27 +65,125 @@ type AccountData struct {
 }
 
 type Account struct {
-	Attributes     *AccountAttributes `json:"attributes,omitempty"`
-	ID             string             `json:"id,omitempty"`
-	OrganisationID string             `json:"organisation_id,omitempty"`
-	Type           string             `json:"type,omitempty"`
-	Version        *int64             `json:"version,omitempty"`
+	Attributes     *AccountAttributes    `json:"attributes,omitempty"`
+	CreatedOn      *time.Time            `json:"created_on,omitempty"`
+	ID             string                `json:"id,omitempty"`
+	ModifiedOn     *time.Time            `json:"modified_on,omitempty"`
+	OrganisationID string                `json:"organisation_id,omitempty"`
+	Relationships  *AccountRelationships `json:"relationships,omitempty"`
+	Type           string                `json:"type,omitempty"`
+	Version        *int64                `json:"version,omitempty"`
 }
 
 type AccountAttributes struct {
-	AccountClassification   *string  `json:"account_classification,omitempty"`
-	AccountMatchingOptOut   *bool    `json:"account_matching_opt_out,omitempty"`
-	AccountNumber           string   `json:"account_number,omitempty"`
-	AlternativeNames        []string `json:"alternative_names,omitempty"`
-	BankID                  string   `json:"bank_id,omitempty"`
-	BankIDCode              string   `json:"bank_id_code,omitempty"`
-	BaseCurrency            string   `json:"base_currency,omitempty"`
-	Bic                     string   `json:"bic,omitempty"`
-	Country                 *string  `json:"country,omitempty"`
-	Iban                    string   `json:"iban,omitempty"`
-	JointAccount            *bool    `json:"joint_account,omitempty"`
-	Name                    []string `json:"name"`
-	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
-	Status                  *string  `json:"status,omitempty"`
-	Switched                *bool    `json:"switched,omitempty"`
+	AcceptanceQualifier        string                      `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      *AccountClassification      `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      *bool                       `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              string                      `json:"account_number,omitempty"`
+	AlternativeNames           []string                    `json:"alternative_names,omitempty"`
+	BankID                     string                      `json:"bank_id,omitempty"`
+	BankIDCode                 BankIDCode                  `json:"bank_id_code,omitempty"`
+	BaseCurrency               string                      `json:"base_currency,omitempty"`
+	Bic                        string                      `json:"bic,omitempty"`
+	Country                    *string                     `json:"country,omitempty"`
+	CustomerID                 string                      `json:"customer_id,omitempty"`
+	Iban                       string                      `json:"iban,omitempty"`
+	JointAccount               *bool                       `json:"joint_account,omitempty"`
+	Name                       []string                    `json:"name"`
+	NameMatchingStatus         string                      `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          string                      `json:"processing_service,omitempty"`
+	ReferenceMask              string                      `json:"reference_mask,omitempty"`
+	SecondaryIdentification    string                      `json:"secondary_identification,omitempty"`
+	Status                     *AccountStatus              `json:"status,omitempty"`
+	StatusReason               string                      `json:"status_reason,omitempty"`
+	Switched                   *bool                       `json:"switched,omitempty"`
+	UserDefinedData            []UserDefinedData           `json:"user_defined_data,omitempty"`
+	UserDefinedInformation     string                      `json:"user_defined_information,omitempty"`
+	ValidationType             string                      `json:"validation_type,omitempty"`
+}
+
+// AccountPatchData is the body of an account update. The version must be the current
+// version of the account, otherwise the API rejects the update as a conflict.
+type AccountPatchData struct {
+	Data AccountPatch `json:"data"`
+}
+
+type AccountPatch struct {
+	Attributes *AccountAttributesPatch `json:"attributes,omitempty"`
+	ID         string                  `json:"id"`
+	Type       string                  `json:"type"`
+	Version    *int64                  `json:"version"`
+}
+
+// AccountAttributesPatch holds the attributes to change. Unset fields are left untouched.
+type AccountAttributesPatch struct {
+	AcceptanceQualifier        string                      `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      *AccountClassification      `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      *bool                       `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              string                      `json:"account_number,omitempty"`
+	AlternativeNames           []string                    `json:"alternative_names,omitempty"`
+	BankID                     string                      `json:"bank_id,omitempty"`
+	BankIDCode                 BankIDCode                  `json:"bank_id_code,omitempty"`
+	BaseCurrency               string                      `json:"base_currency,omitempty"`
+	Bic                        string                      `json:"bic,omitempty"`
+	CustomerID                 string                      `json:"customer_id,omitempty"`
+	Iban                       string                      `json:"iban,omitempty"`
+	JointAccount               *bool                       `json:"joint_account,omitempty"`
+	Name                       []string                    `json:"name,omitempty"`
+	NameMatchingStatus         string                      `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	ProcessingService          string                      `json:"processing_service,omitempty"`
+	ReferenceMask              string                      `json:"reference_mask,omitempty"`
+	SecondaryIdentification    string                      `json:"secondary_identification,omitempty"`
+	Status                     *AccountStatus              `json:"status,omitempty"`
+	StatusReason               string                      `json:"status_reason,omitempty"`
+	Switched                   *bool                       `json:"switched,omitempty"`
+	UserDefinedData            []UserDefinedData           `json:"user_defined_data,omitempty"`
+	UserDefinedInformation     string                      `json:"user_defined_information,omitempty"`
+	ValidationType             string                      `json:"validation_type,omitempty"`
+}
+
+type PrivateIdentification struct {
+	Address        []string `json:"address,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	BirthDate      string   `json:"birth_date,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+}
+
+type OrganisationIdentification struct {
+	Actors         []OrganisationActor `json:"actors,omitempty"`
+	Address        []string            `json:"address,omitempty"`
+	City           string              `json:"city,omitempty"`
+	Country        string              `json:"country,omitempty"`
+	Identification string              `json:"identification,omitempty"`
+}
+
+type OrganisationActor struct {
+	BirthDate string   `json:"birth_date,omitempty"`
+	Name      []string `json:"name,omitempty"`
+	Residency string   `json:"residency,omitempty"`
+}
+
+type UserDefinedData struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type AccountRelationships struct {
+	AccountEvents *RelationshipData `json:"account_events,omitempty"`
+	MasterAccount *RelationshipData `json:"master_account,omitempty"`
+}
+
+type RelationshipData struct {
+	Data []ResourceIdentifier `json:"data,omitempty"`
+}
+
+type ResourceIdentifier struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type,omitempty"`
 }
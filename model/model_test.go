@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fullAccountJson = `{
+	"data": {
+		"attributes": {
+			"acceptance_qualifier": "same_day",
+			"account_classification": "Personal",
+			"account_matching_opt_out": false,
+			"account_number": "41426819",
+			"alternative_names": ["Sam Holder"],
+			"bank_id": "400300",
+			"bank_id_code": "GBDSC",
+			"base_currency": "GBP",
+			"bic": "NWBKGB22",
+			"country": "GB",
+			"customer_id": "customer-1",
+			"iban": "GB16NWBK40030041426819",
+			"joint_account": false,
+			"name": ["Samantha Holder"],
+			"name_matching_status": "supported",
+			"organisation_identification": {
+				"actors": [{"birth_date": "1985-04-12", "name": ["Jane Director"], "residency": "GB"}],
+				"address": ["1 Main Street"],
+				"city": "London",
+				"country": "GB",
+				"identification": "123654"
+			},
+			"private_identification": {
+				"address": ["10 Avenue des Champs"],
+				"birth_country": "GB",
+				"birth_date": "2017-07-23",
+				"city": "London",
+				"country": "GB",
+				"identification": "13YH458762"
+			},
+			"processing_service": "ABC Bank",
+			"reference_mask": "############",
+			"secondary_identification": "A1B2C3D4",
+			"status": "confirmed",
+			"status_reason": "unspecified",
+			"switched": false,
+			"user_defined_data": [{"key": "Some account related key", "value": "Some account related value"}],
+			"user_defined_information": "Some free text",
+			"validation_type": "card"
+		},
+		"created_on": "2022-10-19T09:03:08.334Z",
+		"id": "0d209d7f-d07a-4542-947f-5885fddddae7",
+		"modified_on": "2022-10-19T09:05:40.235Z",
+		"organisation_id": "ba61483c-d5c5-4f50-ae81-6b8c039bea43",
+		"relationships": {
+			"account_events": {"data": [{"id": "c1023677-70ee-417a-9a6a-e211241f1e9c", "type": "account_events"}]},
+			"master_account": {"data": [{"id": "a52d13a4-f435-4c00-cfad-f5e7ac5972df", "type": "accounts"}]}
+		},
+		"type": "accounts",
+		"version": 3
+	}
+}`
+
+func TestAccountRoundTrip(t *testing.T) {
+	var data AccountData
+	err := json.Unmarshal([]byte(fullAccountJson), &data)
+	assert.Empty(t, err, "Error is not empty")
+
+	marshaledData, err := json.Marshal(data)
+	assert.Empty(t, err, "Error is not empty")
+	assert.JSONEq(t, fullAccountJson, string(marshaledData))
+}
+
+func TestAccountTimestamps(t *testing.T) {
+	var data AccountData
+	err := json.Unmarshal([]byte(fullAccountJson), &data)
+	assert.Empty(t, err, "Error is not empty")
+
+	assert.Equal(t, time.Date(2022, 10, 19, 9, 3, 8, 334000000, time.UTC), data.Data.CreatedOn.UTC())
+	assert.Equal(t, time.Date(2022, 10, 19, 9, 5, 40, 235000000, time.UTC), data.Data.ModifiedOn.UTC())
+	assert.Equal(t, "a52d13a4-f435-4c00-cfad-f5e7ac5972df", data.Data.Relationships.MasterAccount.Data[0].ID)
+	assert.Equal(t, "13YH458762", data.Data.Attributes.PrivateIdentification.Identification)
+	assert.Equal(t, "Jane Director", data.Data.Attributes.OrganisationIdentification.Actors[0].Name[0])
+}
+
+func TestAccountWithoutTimestamps(t *testing.T) {
+	data := AccountData{Data: Account{ID: "0d209d7f-d07a-4542-947f-5885fddddae7", Attributes: &AccountAttributes{Name: []string{}}}}
+	marshaledData, err := json.Marshal(data)
+	assert.Empty(t, err, "Error is not empty")
+	assert.JSONEq(t, `{"data": {"attributes": {"name": []}, "id": "0d209d7f-d07a-4542-947f-5885fddddae7"}}`, string(marshaledData))
+}
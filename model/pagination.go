@@ -0,0 +1,127 @@
+package model
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+const pageNumberParameter = "page[number]"
+const pageSizeParameter = "page[size]"
+
+// PageFetcher fetches a single page of accounts, e.g. the GetAccountsPageWithContext of
+// an AccountApi or AccountService with its filters bound.
+type PageFetcher func(ctx context.Context, page PageRequest) (*AccountsData, error)
+
+// AccountIterator walks every account of a listing, following the links.next of each page.
+//
+//	iterator := accountApi.ListAccounts(ctx, model.AccountFilter{Country: "GB"}, 100)
+//	for iterator.Next() {
+//		account := iterator.Account()
+//	}
+//	if err := iterator.Err(); err != nil {
+//	}
+type AccountIterator struct {
+	ctx      context.Context
+	fetch    PageFetcher
+	page     PageRequest
+	accounts []Account
+	index    int
+	current  Account
+	lastPage bool
+	err      error
+}
+
+// NewAccountIterator creates an iterator fetching pages of pageSize accounts through fetch.
+// A pageSize of zero uses the API default.
+func NewAccountIterator(ctx context.Context, pageSize int, fetch PageFetcher) *AccountIterator {
+	return &AccountIterator{
+		ctx:   ctx,
+		fetch: fetch,
+		page:  PageRequest{Size: pageSize},
+	}
+}
+
+// Next advances to the next account, fetching the next page when needed. It returns
+// false once all accounts were visited or an error occurred.
+func (iterator *AccountIterator) Next() bool {
+	for iterator.index >= len(iterator.accounts) {
+		if iterator.lastPage || iterator.err != nil {
+			return false
+		}
+		iterator.fetchPage()
+	}
+
+	iterator.current = iterator.accounts[iterator.index]
+	iterator.index++
+	return true
+}
+
+// Account returns the account Next advanced to.
+func (iterator *AccountIterator) Account() Account {
+	return iterator.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (iterator *AccountIterator) Err() error {
+	return iterator.err
+}
+
+func (iterator *AccountIterator) fetchPage() {
+	data, err := iterator.fetch(iterator.ctx, iterator.page)
+	if err != nil {
+		iterator.err = err
+		return
+	}
+
+	iterator.accounts = data.Data
+	iterator.index = 0
+
+	nextPage, ok := nextPageRequest(data.Links, iterator.page)
+	if !ok || len(data.Data) == 0 {
+		iterator.lastPage = true
+		return
+	}
+	iterator.page = nextPage
+}
+
+// nextPageRequest reads the page to fetch after current from the links of a listing.
+func nextPageRequest(links *Links, current PageRequest) (PageRequest, bool) {
+	if links == nil || links.Next == "" || links.Next == links.Self {
+		return PageRequest{}, false
+	}
+
+	next := PageRequest{Number: current.Number + 1, Size: current.Size}
+	nextUrl, err := url.Parse(links.Next)
+	if err != nil {
+		return next, true
+	}
+
+	query := nextUrl.Query()
+	if number, err := strconv.Atoi(query.Get(pageNumberParameter)); err == nil {
+		next.Number = number
+	}
+	if size, err := strconv.Atoi(query.Get(pageSizeParameter)); err == nil {
+		next.Size = size
+	}
+
+	if next == current {
+		return PageRequest{}, false
+	}
+	return next, true
+}
+
+// QueryParameters returns the page[number] and page[size] query parameters of the page,
+// leaving out the ones left to the API.
+func (page PageRequest) QueryParameters() map[string]string {
+	queryParameters := make(map[string]string)
+	if page == (PageRequest{}) {
+		return queryParameters
+	}
+
+	queryParameters[pageNumberParameter] = strconv.Itoa(page.Number)
+	if page.Size > 0 {
+		queryParameters[pageSizeParameter] = strconv.Itoa(page.Size)
+	}
+	return queryParameters
+}
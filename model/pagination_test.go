@@ -0,0 +1,45 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountIteratorStopsOnRepeatedPage(t *testing.T) {
+	fetches := 0
+	iterator := NewAccountIterator(context.Background(), 1, func(ctx context.Context, page PageRequest) (*AccountsData, error) {
+		fetches++
+		return &AccountsData{
+			Data:  []Account{{ID: "1"}},
+			Links: &Links{Next: "/v1/organisation/accounts?page%5Bnumber%5D=0&page%5Bsize%5D=1"},
+		}, nil
+	})
+
+	count := 0
+	for iterator.Next() {
+		count++
+	}
+	assert.Empty(t, iterator.Err(), "Error is not empty")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, fetches)
+}
+
+func TestAccountIteratorPassesContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	iterator := NewAccountIterator(ctx, 1, func(ctx context.Context, page PageRequest) (*AccountsData, error) {
+		return nil, ctx.Err()
+	})
+
+	assert.False(t, iterator.Next())
+	assert.True(t, errors.Is(iterator.Err(), context.Canceled))
+}
+
+func TestPageRequestQueryParameters(t *testing.T) {
+	assert.Empty(t, PageRequest{}.QueryParameters())
+	assert.Equal(t, map[string]string{"page[number]": "2"}, PageRequest{Number: 2}.QueryParameters())
+	assert.Equal(t, map[string]string{"page[number]": "0", "page[size]": "10"}, PageRequest{Size: 10}.QueryParameters())
+}
@@ -0,0 +1,43 @@
+package model
+
+import "strings"
+
+const (
+	ValidationCodeRequired    = "required"
+	ValidationCodeInvalid     = "invalid"
+	ValidationCodeUnsupported = "unsupported"
+	ValidationCodeNotAllowed  = "not_allowed"
+	ValidationCodeMismatch    = "mismatch"
+)
+
+// ValidationError is a single problem of a request body. Pointer is the JSON pointer of
+// the offending field, e.g. /data/attributes/country, and Code one of the ValidationCode values.
+type ValidationError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (validationError ValidationError) Error() string {
+	return validationError.Message
+}
+
+// ValidationErrors lists every problem of a request body, so all of them can be shown at once.
+type ValidationErrors []ValidationError
+
+func (validationErrors ValidationErrors) Error() string {
+	messages := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		messages = append(messages, validationError.Message)
+	}
+	return "invalid account: " + strings.Join(messages, "; ")
+}
+
+// Pointers returns the JSON pointers of the invalid fields.
+func (validationErrors ValidationErrors) Pointers() []string {
+	pointers := make([]string, 0, len(validationErrors))
+	for _, validationError := range validationErrors {
+		pointers = append(pointers, validationError.Pointer)
+	}
+	return pointers
+}
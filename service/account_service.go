@@ -1,24 +1,46 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"form3-interview-accounts/internal/validation"
 	"form3-interview-accounts/model"
+	"form3-interview-accounts/tracing"
+
+	"github.com/google/uuid"
 )
 
+// ContextAccountOperations is the context-aware counterpart of AccountOperations.
+// Cancelling the context or reaching its deadline aborts the underlying call.
+type ContextAccountOperations interface {
+	GetAccountsWithContext(ctx context.Context, filter model.AccountFilter) ([]model.Account, error)
+	GetAccountsPageWithContext(ctx context.Context, filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error)
+	GetAccountWithContext(ctx context.Context, id string) (*model.Account, error)
+	DeleteAccountWithContext(ctx context.Context, id string, version int) error
+	CreateAccountWithContext(ctx context.Context, accountBody model.AccountData) (*model.Account, error)
+	PatchAccountWithContext(ctx context.Context, id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error)
+	IsHealthyWithContext(ctx context.Context) error
+}
+
 type AccountOperations interface {
-	GetAccounts(filters map[string]string) ([]model.Account, error)
+	ContextAccountOperations
+	GetAccounts(filter model.AccountFilter) ([]model.Account, error)
+	GetAccountsPage(filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error)
 	GetAccount(id string) (*model.Account, error)
 	DeleteAccount(id string, version int) error
 	CreateAccount(accountBody model.AccountData) (*model.Account, error)
+	PatchAccount(id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error)
 	IsHealthy() error
 }
 
 type AccountService struct {
-	accountOperations AccountOperations
+	accountOperations   AccountOperations
+	generateIDs         bool
+	defaultBaseCurrency bool
+	tracer              tracing.Tracer
 }
 
-func NewAccountService(accountOperations AccountOperations) (*AccountService, error) {
+func NewAccountService(accountOperations AccountOperations, options ...Option) (*AccountService, error) {
 	if accountOperations == nil {
 		return nil, fmt.Errorf("error creating account service, fromApi is nil")
 	}
@@ -27,29 +49,130 @@ func NewAccountService(accountOperations AccountOperations) (*AccountService, er
 		accountOperations: accountOperations,
 	}
 
+	for _, option := range options {
+		err := option(&accountService)
+		if err != nil {
+			return nil, fmt.Errorf("invalid account service option. Error: %w", err)
+		}
+	}
+
 	return &accountService, nil
 }
 
-func (accountService AccountService) GetAccounts(filters map[string]string) ([]model.Account, error) {
-	return accountService.accountOperations.GetAccounts(filters)
+func (accountService AccountService) GetAccounts(filter model.AccountFilter) ([]model.Account, error) {
+	return accountService.GetAccountsWithContext(context.Background(), filter)
+}
+
+func (accountService AccountService) GetAccountsWithContext(ctx context.Context, filter model.AccountFilter) ([]model.Account, error) {
+	ctx, span := accountService.startSpan(ctx, "GetAccounts", "", "")
+	accounts, err := accountService.accountOperations.GetAccountsWithContext(ctx, filter)
+	endSpan(span, err)
+	return accounts, err
+}
+
+func (accountService AccountService) GetAccountsPage(filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error) {
+	return accountService.GetAccountsPageWithContext(context.Background(), filter, page)
+}
+
+func (accountService AccountService) GetAccountsPageWithContext(ctx context.Context, filter model.AccountFilter, page model.PageRequest) (*model.AccountsData, error) {
+	ctx, span := accountService.startSpan(ctx, "GetAccountsPage", "", "")
+	data, err := accountService.accountOperations.GetAccountsPageWithContext(ctx, filter, page)
+	endSpan(span, err)
+	return data, err
+}
+
+// ListAccounts returns an iterator over every account matching filter, fetched pageSize at a time.
+func (accountService AccountService) ListAccounts(ctx context.Context, filter model.AccountFilter, pageSize int) *model.AccountIterator {
+	return model.NewAccountIterator(ctx, pageSize, func(ctx context.Context, page model.PageRequest) (*model.AccountsData, error) {
+		return accountService.GetAccountsPageWithContext(ctx, filter, page)
+	})
 }
 
 func (accountService AccountService) GetAccount(id string) (*model.Account, error) {
-	return accountService.accountOperations.GetAccount(id)
+	return accountService.GetAccountWithContext(context.Background(), id)
+}
+
+func (accountService AccountService) GetAccountWithContext(ctx context.Context, id string) (*model.Account, error) {
+	ctx, span := accountService.startSpan(ctx, "GetAccount", id, "")
+	account, err := accountService.accountOperations.GetAccountWithContext(ctx, id)
+	endAccountSpan(span, account, err)
+	return account, err
 }
 
 func (accountService AccountService) DeleteAccount(id string, version int) error {
-	return accountService.accountOperations.DeleteAccount(id, version)
+	return accountService.DeleteAccountWithContext(context.Background(), id, version)
+}
+
+func (accountService AccountService) DeleteAccountWithContext(ctx context.Context, id string, version int) error {
+	ctx, span := accountService.startSpan(ctx, "DeleteAccount", id, "")
+	err := accountService.accountOperations.DeleteAccountWithContext(ctx, id, version)
+	endSpan(span, err)
+	return err
 }
 
 func (accountService AccountService) CreateAccount(accountData model.AccountData) (*model.Account, error) {
+	return accountService.CreateAccountWithContext(context.Background(), accountData)
+}
+
+// CreateAccountWithContext validates the account and creates it. An invalid account is
+// rejected with model.ValidationErrors listing every problem.
+func (accountService AccountService) CreateAccountWithContext(ctx context.Context, accountData model.AccountData) (*model.Account, error) {
+	if accountService.generateIDs && accountData.Data.ID == "" {
+		accountData.Data.ID = uuid.New().String()
+	}
+
+	if accountService.defaultBaseCurrency {
+		accountData.Data.Attributes = withDefaultBaseCurrency(accountData.Data.Attributes)
+	}
+
+	ctx, span := accountService.startSpan(ctx, "CreateAccount", accountData.Data.ID, accountData.Data.OrganisationID)
 	err := validation.ValidateAccount(accountData)
 	if err != nil {
+		endSpan(span, err)
 		return nil, err
 	}
-	return accountService.accountOperations.CreateAccount(accountData)
+	account, err := accountService.accountOperations.CreateAccountWithContext(ctx, accountData)
+	endSpan(span, err)
+	return account, err
+}
+
+// PatchAccount changes the given attributes of the account at its current version. A stale
+// version fails with an error matching api.ErrConflict.
+func (accountService AccountService) PatchAccount(id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error) {
+	return accountService.PatchAccountWithContext(context.Background(), id, version, attributes)
+}
+
+func (accountService AccountService) PatchAccountWithContext(ctx context.Context, id string, version int, attributes model.AccountAttributesPatch) (*model.Account, error) {
+	ctx, span := accountService.startSpan(ctx, "PatchAccount", id, "")
+	account, err := accountService.accountOperations.PatchAccountWithContext(ctx, id, version, attributes)
+	endAccountSpan(span, account, err)
+	return account, err
 }
 
 func (accountService AccountService) IsHealthy() error {
-	return accountService.accountOperations.IsHealthy()
+	return accountService.IsHealthyWithContext(context.Background())
+}
+
+func (accountService AccountService) IsHealthyWithContext(ctx context.Context) error {
+	ctx, span := accountService.startSpan(ctx, "IsHealthy", "", "")
+	err := accountService.accountOperations.IsHealthyWithContext(ctx)
+	endSpan(span, err)
+	return err
+}
+
+// withDefaultBaseCurrency returns a copy of attributes with the default currency of their
+// country when they have no base currency, leaving the caller's attributes untouched.
+func withDefaultBaseCurrency(attributes *model.AccountAttributes) *model.AccountAttributes {
+	if attributes == nil || attributes.Country == nil || attributes.BaseCurrency != "" {
+		return attributes
+	}
+
+	currency, ok := validation.DefaultCurrency(*attributes.Country)
+	if !ok {
+		return attributes
+	}
+
+	attributesCopy := *attributes
+	attributesCopy.BaseCurrency = currency
+	return &attributesCopy
 }
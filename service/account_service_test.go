@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"form3-interview-accounts/api"
 	"form3-interview-accounts/model"
+	"form3-interview-accounts/tracing"
 	"os"
 	"testing"
 	"time"
@@ -21,7 +22,7 @@ func TestMain(m *testing.M) {
 		time.Sleep(5 * time.Second)
 	}
 
-	accounts, err := accountService.GetAccounts(nil)
+	accounts, err := accountService.GetAccounts(model.AccountFilter{})
 	if err != nil {
 		fmt.Printf("unable to fetch accounts. Error: %s\n", err)
 		os.Exit(2)
@@ -50,7 +51,7 @@ func TestServiceCreation(t *testing.T) {
 
 func TestFailedGetAccounts(t *testing.T) {
 	accountService, _ := getAccountService()
-	accounts, err := accountService.GetAccounts(nil)
+	accounts, err := accountService.GetAccounts(model.AccountFilter{})
 	assert.Empty(t, err, "Error is not empty")
 	assert.Empty(t, accounts, "Accounts list is empty")
 }
@@ -68,8 +69,11 @@ func TestCreateAccount(t *testing.T) {
 			Type:           "accounts",
 			Version:        &version,
 			Attributes: &model.AccountAttributes{
-				Name:    names,
-				Country: &country,
+				Name:       names,
+				Country:    &country,
+				BankID:     "400300",
+				BankIDCode: "GBDSC",
+				Bic:        "NWBKGB22",
 			},
 		},
 	}
@@ -93,8 +97,11 @@ func TestCreateSecondAccount(t *testing.T) {
 			Type:           "accounts",
 			Version:        &version,
 			Attributes: &model.AccountAttributes{
-				Name:    names,
-				Country: &country,
+				Name:       names,
+				Country:    &country,
+				BankID:     "400300",
+				BankIDCode: "GBDSC",
+				Bic:        "NWBKGB22",
 			},
 		},
 	}
@@ -107,7 +114,7 @@ func TestCreateSecondAccount(t *testing.T) {
 
 func TestGetAccounts(t *testing.T) {
 	accountService, _ := getAccountService()
-	accounts, err := accountService.GetAccounts(nil)
+	accounts, err := accountService.GetAccounts(model.AccountFilter{})
 	assert.Empty(t, err, "Error is not empty")
 	assert.NotEmpty(t, accounts, "Accounts list is empty")
 	assert.Equal(t, len(accounts), 2)
@@ -150,12 +157,130 @@ func TestErrorDeletingAccount(t *testing.T) {
 	assert.Empty(t, err, "Error is not empty")
 }
 
+func TestCreateAccountWithGeneratedId(t *testing.T) {
+	accountApi, _ := api.NewAccountApi(hostname)
+	accountService, err := NewAccountService(accountApi, WithGeneratedIDs())
+	assert.Empty(t, err, "Error is not empty")
+	country := "GB"
+	createAccount := model.AccountData{
+		Data: model.Account{
+			OrganisationID: uuid.New().String(),
+			Type:           "accounts",
+			Attributes: &model.AccountAttributes{
+				Name:       []string{"Samantha Holder"},
+				Country:    &country,
+				BankID:     "400300",
+				BankIDCode: "GBDSC",
+				Bic:        "NWBKGB22",
+			},
+		},
+	}
+
+	account, err := accountService.CreateAccount(createAccount)
+	assert.Empty(t, err, "Error is not empty")
+	assert.NotEmpty(t, account, "Account is empty")
+	deleteAfterTest(t, accountService, account)
+	_, err = uuid.Parse(account.ID)
+	assert.Empty(t, err, "Generated id is not a UUID")
+	assert.Empty(t, createAccount.Data.ID, "Caller account data was modified")
+}
+
+func TestCreateAccountWithoutIdIsInvalid(t *testing.T) {
+	accountService, _ := getAccountService()
+	country := "GB"
+	createAccount := model.AccountData{
+		Data: model.Account{
+			OrganisationID: uuid.New().String(),
+			Type:           "accounts",
+			Attributes:     &model.AccountAttributes{Country: &country},
+		},
+	}
+
+	account, err := accountService.CreateAccount(createAccount)
+	assert.True(t, api.IsValidationError(err))
+	assert.Empty(t, account, "Account is not empty")
+}
+
+func TestCreateAccountWithDefaultBaseCurrency(t *testing.T) {
+	accountApi, _ := api.NewAccountApi(hostname)
+	accountService, err := NewAccountService(accountApi, WithDefaultBaseCurrency())
+	assert.Empty(t, err, "Error is not empty")
+	country := "GB"
+	createAccount := model.AccountData{
+		Data: model.Account{
+			ID:             uuid.New().String(),
+			OrganisationID: uuid.New().String(),
+			Type:           "accounts",
+			Attributes: &model.AccountAttributes{
+				Name:       []string{"Samantha Holder"},
+				Country:    &country,
+				BankID:     "400300",
+				BankIDCode: "GBDSC",
+				Bic:        "NWBKGB22",
+			},
+		},
+	}
+
+	account, err := accountService.CreateAccount(createAccount)
+	assert.Empty(t, err, "Error is not empty")
+	deleteAfterTest(t, accountService, account)
+	assert.Equal(t, "GBP", account.Attributes.BaseCurrency)
+	assert.Empty(t, createAccount.Data.Attributes.BaseCurrency, "Caller account data was modified")
+}
+
+func TestCreateAccountIsTraced(t *testing.T) {
+	recorder := tracing.NewRecorder()
+	accountApi, _ := api.NewAccountApi(hostname, api.WithTracer(recorder))
+	accountService, err := NewAccountService(accountApi, WithTracer(recorder))
+	assert.Empty(t, err, "Error is not empty")
+	country := "GB"
+	createAccount := model.AccountData{
+		Data: model.Account{
+			ID:             uuid.New().String(),
+			OrganisationID: uuid.New().String(),
+			Type:           "accounts",
+			Attributes: &model.AccountAttributes{
+				Name:       []string{"Samantha Holder"},
+				Country:    &country,
+				BankID:     "400300",
+				BankIDCode: "GBDSC",
+				Bic:        "NWBKGB22",
+			},
+		},
+	}
+
+	account, err := accountService.CreateAccount(createAccount)
+	assert.Empty(t, err, "Error is not empty")
+	deleteAfterTest(t, accountService, account)
+
+	spans := recorder.Spans()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, "AccountService.CreateAccount", spans[0].Name)
+	assert.Equal(t, createAccount.Data.ID, spans[0].Attributes[tracing.AttributeAccountID])
+	assert.Equal(t, createAccount.Data.OrganisationID, spans[0].Attributes[tracing.AttributeOrganisationID])
+	assert.Equal(t, "AccountApi.create", spans[1].Name)
+	assert.Equal(t, spans[0].SpanContext, spans[1].Parent)
+	assert.Equal(t, 201, spans[1].Attributes[tracing.AttributeHTTPStatusCode])
+}
+
+// deleteAfterTest deletes an account created by a test once it finishes, so the shared
+// account API is left as the other tests expect it.
+func deleteAfterTest(t *testing.T, accountService *AccountService, account *model.Account) {
+	if account == nil {
+		return
+	}
+	t.Cleanup(func() {
+		err := accountService.DeleteAccount(account.ID, int(*account.Version))
+		assert.Empty(t, err, "Error deleting account created by the test")
+	})
+}
+
 func getAccountService() (*AccountService, error) {
 	accountApi, _ := api.NewAccountApi(hostname)
 	return NewAccountService(accountApi)
 }
 
 func getAccountId(accountService *AccountService) (model.Account, error) {
-	accounts, err := accountService.GetAccounts(nil)
+	accounts, err := accountService.GetAccounts(model.AccountFilter{})
 	return accounts[0], err
 }
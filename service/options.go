@@ -0,0 +1,21 @@
+package service
+
+// Option configures an AccountService created by NewAccountService.
+type Option func(*AccountService) error
+
+// WithGeneratedIDs makes CreateAccount generate a UUID for accounts created without an ID.
+func WithGeneratedIDs() Option {
+	return func(accountService *AccountService) error {
+		accountService.generateIDs = true
+		return nil
+	}
+}
+
+// WithDefaultBaseCurrency makes CreateAccount fill in the usual currency of the account
+// country for accounts created without a base currency.
+func WithDefaultBaseCurrency() Option {
+	return func(accountService *AccountService) error {
+		accountService.defaultBaseCurrency = true
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"form3-interview-accounts/model"
+	"form3-interview-accounts/tracing"
+)
+
+// WithTracer starts a span for every AccountService operation. The spans of the
+// underlying AccountApi calls become its children when the api uses a tracer too.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(accountService *AccountService) error {
+		if tracer == nil {
+			return fmt.Errorf("tracer must not be nil")
+		}
+		accountService.tracer = tracer
+		return nil
+	}
+}
+
+func (accountService AccountService) startSpan(ctx context.Context, operation string, id string, organisationID string) (context.Context, tracing.Span) {
+	tracer := accountService.tracer
+	if tracer == nil {
+		tracer = tracing.NoopTracer{}
+	}
+
+	ctx, span := tracer.Start(ctx, "AccountService."+operation)
+	if id != "" {
+		span.SetAttribute(tracing.AttributeAccountID, id)
+	}
+	if organisationID != "" {
+		span.SetAttribute(tracing.AttributeOrganisationID, organisationID)
+	}
+	return ctx, span
+}
+
+// endAccountSpan ends span, adding the organisation of account, when known, and err.
+func endAccountSpan(span tracing.Span, account *model.Account, err error) {
+	if account != nil && account.OrganisationID != "" {
+		span.SetAttribute(tracing.AttributeOrganisationID, account.OrganisationID)
+	}
+	endSpan(span, err)
+}
+
+func endSpan(span tracing.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+)
+
+// RecordedSpan is a snapshot of a span started by a Recorder.
+type RecordedSpan struct {
+	Name        string
+	SpanContext SpanContext
+	Parent      SpanContext
+	Attributes  map[string]any
+	Errors      []error
+	Ended       bool
+}
+
+// Recorder is an in-memory Tracer for tests. Every span it starts is sampled.
+type Recorder struct {
+	mutex sync.Mutex
+	spans []*recordingSpan
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (recorder *Recorder) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent := SpanFromContext(ctx).SpanContext()
+	spanContext := SpanContext{TraceID: parent.TraceID, Sampled: true}
+	if !parent.IsValid() {
+		_, _ = rand.Read(spanContext.TraceID[:])
+	}
+	_, _ = rand.Read(spanContext.SpanID[:])
+
+	span := &recordingSpan{
+		recorder: recorder,
+		recorded: RecordedSpan{
+			Name:        name,
+			SpanContext: spanContext,
+			Parent:      parent,
+			Attributes:  map[string]any{},
+		},
+	}
+	recorder.mutex.Lock()
+	recorder.spans = append(recorder.spans, span)
+	recorder.mutex.Unlock()
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Spans returns the spans started so far, in start order.
+func (recorder *Recorder) Spans() []RecordedSpan {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	spans := make([]RecordedSpan, 0, len(recorder.spans))
+	for _, span := range recorder.spans {
+		recorded := span.recorded
+		recorded.Attributes = make(map[string]any, len(span.recorded.Attributes))
+		for key, value := range span.recorded.Attributes {
+			recorded.Attributes[key] = value
+		}
+		recorded.Errors = append([]error{}, span.recorded.Errors...)
+		spans = append(spans, recorded)
+	}
+	return spans
+}
+
+// Reset forgets every recorded span.
+func (recorder *Recorder) Reset() {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.spans = nil
+}
+
+type recordingSpan struct {
+	recorder *Recorder
+	recorded RecordedSpan
+}
+
+func (span *recordingSpan) SpanContext() SpanContext {
+	return span.recorded.SpanContext
+}
+
+func (span *recordingSpan) SetAttribute(key string, value any) {
+	span.recorder.mutex.Lock()
+	defer span.recorder.mutex.Unlock()
+	span.recorded.Attributes[key] = value
+}
+
+func (span *recordingSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	span.recorder.mutex.Lock()
+	defer span.recorder.mutex.Unlock()
+	span.recorded.Errors = append(span.recorded.Errors, err)
+}
+
+func (span *recordingSpan) End() {
+	span.recorder.mutex.Lock()
+	defer span.recorder.mutex.Unlock()
+	span.recorded.Ended = true
+}
@@ -0,0 +1,146 @@
+// Package tracing defines the small tracing API used by the account client. Its Tracer and
+// Span interfaces mirror the OpenTelemetry ones, so an OpenTelemetry tracer can be plugged
+// in with a thin adapter. Without one, NoopTracer keeps tracing disabled.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TraceParentHeader carries the W3C trace context of outgoing requests.
+const TraceParentHeader = "traceparent"
+
+// Tracer starts spans. The span is a child of the span in ctx, if any, and the returned
+// context carries the new span.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation. End must be called once the operation is finished.
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid reports whether both ids are set, as W3C trace context requires.
+func (spanContext SpanContext) IsValid() bool {
+	return spanContext.TraceID != [16]byte{} && spanContext.SpanID != [8]byte{}
+}
+
+// TraceParent formats the span context as a version 00 traceparent header value.
+func (spanContext SpanContext) TraceParent() string {
+	flags := "00"
+	if spanContext.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(spanContext.TraceID[:]), hex.EncodeToString(spanContext.SpanID[:]), flags)
+}
+
+// ParseTraceParent parses a traceparent header value.
+func ParseTraceParent(traceParent string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q", traceParent)
+	}
+
+	var spanContext SpanContext
+	var flags [1]byte
+	if err := decodeHex(parts[1], spanContext.TraceID[:]); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace id in traceparent %q. Error: %w", traceParent, err)
+	}
+	if err := decodeHex(parts[2], spanContext.SpanID[:]); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid span id in traceparent %q. Error: %w", traceParent, err)
+	}
+	if err := decodeHex(parts[3], flags[:]); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid flags in traceparent %q. Error: %w", traceParent, err)
+	}
+	if !spanContext.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent %q, ids must not be zero", traceParent)
+	}
+	spanContext.Sampled = flags[0]&1 == 1
+
+	return spanContext, nil
+}
+
+func decodeHex(value string, target []byte) error {
+	if len(value) != 2*len(target) || strings.ToLower(value) != value {
+		return fmt.Errorf("expected %d lowercase hex characters, got %q", 2*len(target), value)
+	}
+	_, err := hex.Decode(target, []byte(value))
+	return err
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span.
+func ContextWithSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the span in ctx, or a non-recording span without a valid span
+// context when there is none.
+func SpanFromContext(ctx context.Context) Span {
+	if span, ok := ctx.Value(spanContextKey{}).(Span); ok {
+		return span
+	}
+	return noopSpan{}
+}
+
+// Inject sets the traceparent header of the span in ctx, if it has a valid span context.
+func Inject(ctx context.Context, header http.Header) {
+	spanContext := SpanFromContext(ctx).SpanContext()
+	if spanContext.IsValid() {
+		header.Set(TraceParentHeader, spanContext.TraceParent())
+	}
+}
+
+// Extract returns a copy of ctx whose span is the remote parent described by the
+// traceparent header, so spans started from it join the caller's trace. ctx is returned
+// as is when the header is missing or invalid.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	spanContext, err := ParseTraceParent(header.Get(TraceParentHeader))
+	if err != nil {
+		return ctx
+	}
+	return ContextWithSpan(ctx, noopSpan{spanContext: spanContext})
+}
+
+// NoopTracer records nothing. Its spans carry on the span context of their parent, so
+// trace context received from callers is still propagated.
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	span := noopSpan{spanContext: SpanFromContext(ctx).SpanContext()}
+	return ContextWithSpan(ctx, span), span
+}
+
+type noopSpan struct {
+	spanContext SpanContext
+}
+
+func (span noopSpan) SpanContext() SpanContext { return span.spanContext }
+func (noopSpan) SetAttribute(_ string, _ any)  {}
+func (noopSpan) RecordError(_ error)           {}
+func (noopSpan) End()                          {}
+
+// Attributes set on the account client spans.
+const (
+	AttributeAccountID      = "account.id"
+	AttributeOrganisationID = "account.organisation_id"
+	AttributeHTTPMethod     = "http.request.method"
+	AttributeHTTPStatusCode = "http.response.status_code"
+	AttributeURLPath        = "url.path"
+)
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	spanContext, err := ParseTraceParent(traceParent)
+	assert.Empty(t, err, "Error is not empty")
+	assert.True(t, spanContext.IsValid())
+	assert.True(t, spanContext.Sampled)
+	assert.Equal(t, traceParent, spanContext.TraceParent())
+
+	invalidTraceParents := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	}
+	for _, invalid := range invalidTraceParents {
+		_, err = ParseTraceParent(invalid)
+		assert.NotEmpty(t, err, "Error is empty for %q", invalid)
+	}
+}
+
+func TestRecorderParentsSpans(t *testing.T) {
+	recorder := NewRecorder()
+
+	ctx, parent := recorder.Start(context.Background(), "parent")
+	_, child := recorder.Start(ctx, "child")
+	child.SetAttribute("account.id", "1")
+	child.RecordError(errors.New("failed"))
+	child.End()
+
+	spans := recorder.Spans()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, parent.SpanContext(), spans[1].Parent)
+	assert.Equal(t, parent.SpanContext().TraceID, spans[1].SpanContext.TraceID)
+	assert.NotEqual(t, parent.SpanContext().SpanID, spans[1].SpanContext.SpanID)
+	assert.Equal(t, "1", spans[1].Attributes["account.id"])
+	assert.Equal(t, 1, len(spans[1].Errors))
+	assert.True(t, spans[1].Ended)
+	assert.False(t, spans[0].Ended)
+}
+
+func TestInjectAndExtract(t *testing.T) {
+	incoming := http.Header{}
+	incoming.Set(TraceParentHeader, traceParent)
+	ctx := Extract(context.Background(), incoming)
+
+	ctx, span := NewRecorder().Start(ctx, "operation")
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+
+	sent, err := ParseTraceParent(outgoing.Get(TraceParentHeader))
+	assert.Empty(t, err, "Error is not empty")
+	assert.Equal(t, span.SpanContext(), sent)
+	assert.Contains(t, outgoing.Get(TraceParentHeader), traceParent[3:35])
+}
+
+func TestNoopTracer(t *testing.T) {
+	ctx, span := NoopTracer{}.Start(context.Background(), "operation")
+	assert.False(t, span.SpanContext().IsValid())
+	outgoing := http.Header{}
+	Inject(ctx, outgoing)
+	assert.Empty(t, outgoing.Get(TraceParentHeader))
+
+	incoming := http.Header{}
+	incoming.Set(TraceParentHeader, traceParent)
+	ctx, _ = NoopTracer{}.Start(Extract(context.Background(), incoming), "operation")
+	Inject(ctx, outgoing)
+	assert.Equal(t, traceParent, outgoing.Get(TraceParentHeader))
+}